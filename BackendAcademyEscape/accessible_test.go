@@ -0,0 +1,113 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestAccessibilityModeReadsListsAndExitsOut(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "accessibility", "on")
+
+	//Act
+	look := run(game, "look")
+
+	//Assert
+	if !strings.Contains(look.Message, "You can approach 4 things: ") {
+		t.Errorf("Expected the entities to be counted, got %q", look.Message)
+	}
+	if !strings.HasSuffix(look.Message, "Exits are: south (locked).\n") {
+		t.Errorf("Expected the exits to be read out, got %q", look.Message)
+	}
+	if strings.Contains(look.Message, "\n\n") || strings.Contains(look.Message, "- ") {
+		t.Errorf("Expected no blank lines or bullets, got %q", look.Message)
+	}
+}
+
+func TestAccessibilityModeReadsOutInFrench(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "language", "fr")
+	run(game, "accessibility", "on")
+	unlockAlansComputer(game)
+
+	//Act
+	look := run(game, "look")
+	computer := run(game, "approach", "computer")
+
+	//Assert
+	if !strings.Contains(look.Message, "Vous pouvez approcher 4 choses : agile-manifesto, alan, computer et desk (approché).\n") {
+		t.Errorf("Expected the entities to be counted in French, got %q", look.Message)
+	}
+	if !strings.HasSuffix(look.Message, "Les sorties sont : east et north.\n") {
+		t.Errorf("Expected the exits to be read out in French, got %q", look.Message)
+	}
+	if !strings.Contains(computer.Message, "Liste de code, 5 lignes :\n") || !strings.HasSuffix(computer.Message, "Fin de la liste de code.\n") {
+		t.Errorf("Expected the code listing to be announced in French, got %q", computer.Message)
+	}
+}
+
+func TestAccessibleDisplayAnnouncesCodeListings(t *testing.T) {
+	//Arrange
+	display := model.AccessibleDisplay{}
+
+	//Act
+	shown := display.Show("The screen reads:\n\n{code}one\n  two{/code}\n\nDone — for now.\n")
+
+	//Assert
+	expected := "The screen reads:\nCode listing, 2 lines:\none\ntwo\nEnd of code listing.\nDone, for now.\n"
+	if shown != expected {
+		t.Errorf("Expected %q, got %q", expected, shown)
+	}
+}
+
+func TestBriefDescribesARoomOnlyTheFirstTime(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "brief")
+	run(game, "look")
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+
+	//Act
+	arrived := run(game, "move", "south")
+	again := run(game, "look")
+	back := run(game, "move", "north")
+
+	//Assert
+	if !strings.Contains(arrived.Message, "A bright, tech-filled room") {
+		t.Errorf("Expected the new room to be described, got %q", arrived.Message)
+	}
+	if strings.Contains(again.Message, "A bright, tech-filled room") {
+		t.Errorf("Expected the description to be left out, got %q", again.Message)
+	}
+	if !strings.HasSuffix(back.Message, "You are in break-room\n") || strings.Contains(back.Message, "A cozy lounge") {
+		t.Errorf("Expected just the room name in a room seen before, got %q", back.Message)
+	}
+}
+
+func TestVerboseDescribesTheRoomAfterEveryMove(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	quiet := run(game, "move", "south")
+	run(game, "verbose")
+
+	//Act
+	back := run(game, "move", "north")
+
+	//Assert
+	if strings.Contains(quiet.Message, "You can approach") {
+		t.Errorf("Expected only the room name by default, got %q", quiet.Message)
+	}
+	if !strings.Contains(back.Message, "You are in break-room\n\nA cozy lounge") {
+		t.Errorf("Expected the room to be described, got %q", back.Message)
+	}
+}
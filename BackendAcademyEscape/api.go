@@ -0,0 +1,513 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/api/v1"
+
+const maxRequestBodyBytes = 1 << 20
+
+const (
+	errorInvalidInput     = "invalid_input"
+	errorNotFound         = "not_found"
+	errorSessionNotFound  = "session_not_found"
+	errorGameOver         = "game_over"
+	errorMethodNotAllowed = "method_not_allowed"
+	errorUnauthorized     = "unauthorized"
+	errorInternal         = "internal_error"
+	errorResumeIncomplete = "resume_incomplete"
+	errorWorldNotFound    = "world_not_found"
+	errorNameTaken        = "name_taken"
+	errorNoCountdown      = "no_countdown"
+)
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+const maxPlayerNameLength = 40
+
+const defaultPlayerName = "anonymous"
+
+var errEmptyBody = errors.New("Request body must not be empty.")
+
+// NewSession starts a new world unless WorldID names one to join.
+// DisableChat and TimeLimitSeconds only apply to a new world.
+type NewSession struct {
+	PlayerName       string `json:"player_name,omitempty"`
+	WorldID          string `json:"world_id,omitempty"`
+	DisableChat      bool   `json:"disable_chat,omitempty"`
+	TimeLimitSeconds int    `json:"time_limit_seconds,omitempty"`
+	// Format is how responses are rendered: plain (the default), ansi,
+	// html or json.
+	Format string `json:"format,omitempty"`
+}
+
+type SessionCreated struct {
+	ID      string `json:"id"`
+	WorldID string `json:"world_id"`
+	model.GameResponse
+}
+
+type SessionView struct {
+	ID        string    `json:"id"`
+	WorldID   string    `json:"world_id"`
+	Room      string    `json:"room"`
+	GameOver  bool      `json:"game_over"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// route describes an endpoint well enough to both register it and document
+// it in the OpenAPI specification. Responses maps each status code the
+// handler can write to a value of its body type, or nil for an empty body.
+// Facilitator routes are wrapped in facilitatorOnly when registered.
+type route struct {
+	Method          string
+	Pattern         string
+	Handler         http.HandlerFunc
+	Summary         string
+	Query           []queryParam
+	Request         any
+	RequestOptional bool
+	Facilitator     bool
+	Responses       map[int]any
+}
+
+type queryParam struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+type server struct {
+	sessions         *SessionStore
+	facilitatorToken string
+}
+
+func newServer() *server {
+	return &server{sessions: NewSessionStore()}
+}
+
+func (s *server) routes() []route {
+	return []route{
+		{
+			Method:          http.MethodPost,
+			Pattern:         apiPrefix + "/sessions",
+			Handler:         s.createSession,
+			Summary:         "Start a new game, or join another player's world, and show its introduction in the best language from Accept-Language",
+			Request:         NewSession{},
+			RequestOptional: true,
+			Responses: map[int]any{
+				http.StatusCreated:             SessionCreated{},
+				http.StatusBadRequest:          ErrorResponse{},
+				http.StatusNotFound:            ErrorResponse{},
+				http.StatusConflict:            ErrorResponse{},
+				http.StatusInternalServerError: ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}",
+			Handler: s.getSession,
+			Summary: "Show the current room and whether the game is over",
+			Responses: map[int]any{
+				http.StatusOK:       SessionView{},
+				http.StatusNotFound: ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: apiPrefix + "/sessions/{id}",
+			Handler: s.deleteSession,
+			Summary: "End a session",
+			Responses: map[int]any{
+				http.StatusNoContent: nil,
+				http.StatusNotFound:  ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: apiPrefix + "/sessions/{id}/commands",
+			Handler: s.runCommand,
+			Summary: "Run a command in the game",
+			Request: model.PlayerInput{},
+			Responses: map[int]any{
+				http.StatusOK:         model.GameResponse{},
+				http.StatusBadRequest: ErrorResponse{},
+				http.StatusNotFound:   ErrorResponse{},
+				http.StatusConflict:   ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}/ws",
+			Handler: s.connectWebSocket,
+			Summary: "Play over a WebSocket carrying JSON frames",
+			Query: []queryParam{
+				{Name: "last_seq", Description: "The seq of the last frame received, to resume after reconnecting."},
+			},
+			Responses: map[int]any{
+				http.StatusSwitchingProtocols: nil,
+				http.StatusBadRequest:         ErrorResponse{},
+				http.StatusNotFound:           ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}/events",
+			Handler: s.streamEvents,
+			Summary: "Stream the session's game events as Server-Sent Events",
+			Query: []queryParam{
+				{Name: "last_event_id", Description: "Resume after this event id, for clients that cannot send the Last-Event-ID header."},
+			},
+			Responses: map[int]any{
+				http.StatusOK:         eventStream{Event: model.GameEvent{}},
+				http.StatusBadRequest: ErrorResponse{},
+				http.StatusNotFound:   ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}/actions",
+			Handler: s.getActions,
+			Summary: "List the arguments available for a command",
+			Query: []queryParam{
+				{Name: "command", Description: "The command to list arguments for, e.g. take.", Required: true},
+			},
+			Responses: map[int]any{
+				http.StatusOK:         model.GameActions{},
+				http.StatusBadRequest: ErrorResponse{},
+				http.StatusNotFound:   ErrorResponse{},
+				http.StatusConflict:   ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}/map",
+			Handler: s.getMap,
+			Summary: "Lay out the rooms the player has explored on a grid",
+			Responses: map[int]any{
+				http.StatusOK:       model.MapLayout{},
+				http.StatusNotFound: ErrorResponse{},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: apiPrefix + "/sessions/{id}/map.svg",
+			Handler: s.getMapSVG,
+			Summary: "Draw the rooms the player has explored as an SVG image",
+			Responses: map[int]any{
+				http.StatusOK:       svgImage{},
+				http.StatusNotFound: ErrorResponse{},
+			},
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     apiPrefix + "/facilitator/sessions",
+			Handler:     s.listSessions,
+			Summary:     "List every session with the player's progress and idle time",
+			Facilitator: true,
+			Responses: map[int]any{
+				http.StatusOK:           SessionList{},
+				http.StatusUnauthorized: ErrorResponse{},
+			},
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     apiPrefix + "/facilitator/sessions/{id}/transcript",
+			Handler:     s.getTranscript,
+			Summary:     "Show the commands and responses of a session",
+			Facilitator: true,
+			Query: []queryParam{
+				{Name: "after", Description: "Only return entries with a greater seq, to tail the transcript."},
+			},
+			Responses: map[int]any{
+				http.StatusOK:           Transcript{},
+				http.StatusBadRequest:   ErrorResponse{},
+				http.StatusUnauthorized: ErrorResponse{},
+				http.StatusNotFound:     ErrorResponse{},
+			},
+		},
+		{
+			Method:      http.MethodPost,
+			Pattern:     apiPrefix + "/facilitator/sessions/{id}/messages",
+			Handler:     s.sendFacilitatorMessage,
+			Summary:     "Send a message that appears in the player's next response",
+			Facilitator: true,
+			Request:     FacilitatorMessage{},
+			Responses: map[int]any{
+				http.StatusNoContent:    nil,
+				http.StatusBadRequest:   ErrorResponse{},
+				http.StatusUnauthorized: ErrorResponse{},
+				http.StatusNotFound:     ErrorResponse{},
+			},
+		},
+		{
+			Method:      http.MethodPost,
+			Pattern:     apiPrefix + "/facilitator/worlds/{id}/countdown",
+			Handler:     s.changeCountdown,
+			Summary:     "Pause, resume, extend or reset a world's time limit",
+			Facilitator: true,
+			Request:     CountdownChange{},
+			Responses: map[int]any{
+				http.StatusOK:           CountdownView{},
+				http.StatusBadRequest:   ErrorResponse{},
+				http.StatusUnauthorized: ErrorResponse{},
+				http.StatusNotFound:     ErrorResponse{},
+				http.StatusConflict:     ErrorResponse{},
+			},
+		},
+	}
+}
+
+// handler registers every route on a mux, answering requests that use an
+// unsupported method with a JSON 405 instead of the mux's plain text one.
+func (s *server) handler() http.Handler {
+	router := http.NewServeMux()
+	router.HandleFunc("/", rootHandler)
+	router.HandleFunc(apiPrefix+"/", func(writer http.ResponseWriter, request *http.Request) {
+		writeError(writer, http.StatusNotFound, errorNotFound, fmt.Sprintf("No endpoint at %s.", request.URL.Path))
+	})
+
+	byPattern := make(map[string]map[string]http.HandlerFunc)
+	var patterns []string
+	for _, r := range s.routes() {
+		if _, ok := byPattern[r.Pattern]; !ok {
+			byPattern[r.Pattern] = make(map[string]http.HandlerFunc)
+			patterns = append(patterns, r.Pattern)
+		}
+		handler := r.Handler
+		if r.Facilitator {
+			handler = s.facilitatorOnly(handler)
+		}
+		byPattern[r.Pattern][r.Method] = handler
+	}
+
+	for _, pattern := range patterns {
+		router.HandleFunc(pattern, methodRouter(byPattern[pattern]))
+	}
+
+	router.HandleFunc("/openapi.json", methodRouter(map[string]http.HandlerFunc{http.MethodGet: s.getOpenAPI}))
+	return router
+}
+
+func methodRouter(handlers map[string]http.HandlerFunc) http.HandlerFunc {
+	var allowed []string
+	for method := range handlers {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if handler, ok := handlers[request.Method]; ok {
+			handler(writer, request)
+			return
+		}
+		writer.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeError(writer, http.StatusMethodNotAllowed, errorMethodNotAllowed, fmt.Sprintf("Method %s is not allowed, use %s.", request.Method, strings.Join(allowed, " or ")))
+	}
+}
+
+func (s *server) createSession(writer http.ResponseWriter, request *http.Request) {
+	var body NewSession
+	if err := decodeBody(writer, request, &body); err != nil && err != errEmptyBody {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, err.Error())
+		return
+	}
+	playerName := strings.TrimSpace(body.PlayerName)
+	if len(playerName) > maxPlayerNameLength {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, fmt.Sprintf("The player name must not exceed %d characters.", maxPlayerNameLength))
+		return
+	}
+	if (body.DisableChat || body.TimeLimitSeconds != 0) && body.WorldID != "" {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, "Chat and the time limit can only be set when starting a new world.")
+		return
+	}
+	if body.TimeLimitSeconds < 0 {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, "The time limit must not be negative.")
+		return
+	}
+	if playerName == "" && body.WorldID == "" {
+		playerName = defaultPlayerName
+	}
+	if _, ok := model.Displays[body.Format]; body.Format != "" && !ok {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, "The format must be plain, ansi, html, json or accessible.")
+		return
+	}
+
+	session, err := s.sessions.Create(playerName, body.WorldID, WorldOptions{
+		DisableChat: body.DisableChat,
+		TimeLimit:   time.Duration(body.TimeLimitSeconds) * time.Second,
+	})
+	switch {
+	case errors.Is(err, errWorldNotFound):
+		writeError(writer, http.StatusNotFound, errorWorldNotFound, fmt.Sprintf("World %s does not exist.", body.WorldID))
+		return
+	case errors.Is(err, model.ErrNameTaken):
+		writeError(writer, http.StatusConflict, errorNameTaken, fmt.Sprintf("A player called %s is already in this world.", playerName))
+		return
+	case errors.Is(err, model.ErrGameOver):
+		writeError(writer, http.StatusConflict, errorGameOver, "The game in this world is over, start a new session to play again.")
+		return
+	case err != nil:
+		fmt.Println("Error creating session:", err)
+		writeError(writer, http.StatusInternalServerError, errorInternal, "Could not create a session.")
+		return
+	}
+
+	if body.Format != "" {
+		session.Game.SetDisplay(session.PlayerID, body.Format)
+	}
+	if acceptLanguage := request.Header.Get("Accept-Language"); acceptLanguage != "" {
+		session.Game.SetLocale(session.PlayerID, session.Game.MatchLocale(acceptLanguage))
+	}
+	response := session.Run(model.PlayerInput{Command: "start", Args: []string{}})
+
+	writer.Header().Set("Location", apiPrefix+"/sessions/"+session.ID)
+	writeJSON(writer, http.StatusCreated, SessionCreated{ID: session.ID, WorldID: session.WorldID, GameResponse: response})
+}
+
+func (s *server) getSession(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	progress, _ := session.Game.Progress(session.PlayerID)
+	writeJSON(writer, http.StatusOK, SessionView{
+		ID:        session.ID,
+		WorldID:   session.WorldID,
+		Room:      progress.Room,
+		GameOver:  progress.GameOver,
+		CreatedAt: session.CreatedAt,
+	})
+}
+
+func (s *server) deleteSession(writer http.ResponseWriter, request *http.Request) {
+	id := request.PathValue("id")
+	if !s.sessions.Delete(id) {
+		writeError(writer, http.StatusNotFound, errorSessionNotFound, fmt.Sprintf("Session %s does not exist.", id))
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) runCommand(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	var playerInput model.PlayerInput
+	if err := decodeBody(writer, request, &playerInput); err != nil {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, err.Error())
+		return
+	}
+	if strings.TrimSpace(playerInput.Command) == "" {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, "The command must not be empty.")
+		return
+	}
+
+	if session.Game.IsOverFor(session.PlayerID) {
+		writeError(writer, http.StatusConflict, errorGameOver, "The game is over, start a new session to play again.")
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, session.Run(playerInput))
+}
+
+func (s *server) getActions(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	command := request.URL.Query().Get("command")
+	if command == "" {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, "The command query parameter is required.")
+		return
+	}
+
+	if session.Game.IsOverFor(session.PlayerID) {
+		writeError(writer, http.StatusConflict, errorGameOver, "The game is over, start a new session to play again.")
+		return
+	}
+
+	actions, _ := session.Game.GetAvailableActionsFor(session.PlayerID, command)
+	writeJSON(writer, http.StatusOK, actions)
+}
+
+func (s *server) getMap(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	layout, _ := session.Game.Map(session.PlayerID)
+	writeJSON(writer, http.StatusOK, layout)
+}
+
+// svgImage documents an image/svg+xml response.
+type svgImage struct{}
+
+func (s *server) getMapSVG(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	layout, _ := session.Game.Map(session.PlayerID)
+	writer.Header().Set("Content-Type", "image/svg+xml")
+	writer.WriteHeader(http.StatusOK)
+	io.WriteString(writer, layout.SVG())
+}
+
+func (s *server) lookupSession(writer http.ResponseWriter, request *http.Request) (*Session, bool) {
+	id := request.PathValue("id")
+	session, ok := s.sessions.Get(id)
+	if !ok {
+		writeError(writer, http.StatusNotFound, errorSessionNotFound, fmt.Sprintf("Session %s does not exist.", id))
+	}
+	return session, ok
+}
+
+func decodeBody(writer http.ResponseWriter, request *http.Request, target any) error {
+	request.Body = http.MaxBytesReader(writer, request.Body, maxRequestBodyBytes)
+	if err := json.NewDecoder(request.Body).Decode(target); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errEmptyBody
+		}
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return fmt.Errorf("Request body must not exceed %d bytes.", maxBytesError.Limit)
+		}
+		return errors.New("Request body must be valid JSON.")
+	}
+	return nil
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	if err := json.NewEncoder(writer).Encode(body); err != nil {
+		fmt.Println("Error encoding response:", err)
+	}
+}
+
+func writeError(writer http.ResponseWriter, status int, code string, message string) {
+	writeJSON(writer, status, ErrorResponse{Error: APIError{Code: code, Message: message}})
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func performRequest(handler http.Handler, method string, path string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func createTestSession(t *testing.T, handler http.Handler) SessionCreated {
+	t.Helper()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", "")
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d creating a session, got %d", http.StatusCreated, recorder.Code)
+	}
+	var created SessionCreated
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func decodeError(t *testing.T, recorder *httptest.ResponseRecorder) APIError {
+	t.Helper()
+	var response ErrorResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected a JSON error body, got %q", recorder.Body.String())
+	}
+	return response.Error
+}
+
+func TestCreateSessionShowsIntroduction(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+
+	//Act
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", "")
+
+	//Assert
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	var created SessionCreated
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" {
+		t.Errorf("Expected a session id, got an empty string")
+	}
+	if !strings.HasPrefix(created.Message, "It's the last day at the Academy") {
+		t.Errorf("Expected the introduction, got %q", created.Message)
+	}
+	if location := recorder.Header().Get("Location"); location != "/api/v1/sessions/"+created.ID {
+		t.Errorf("Expected Location header for the session, got %q", location)
+	}
+}
+
+func TestSessionsAreIndependent(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	first := createTestSession(t, handler)
+	second := createTestSession(t, handler)
+
+	//Act
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+first.ID+"/commands", `{"command":"exit","args":[]}`)
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+second.ID+"/commands", `{"command":"look","args":[]}`)
+
+	//Assert
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status %d for the second session, got %d", http.StatusOK, recorder.Code)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	session := createTestSession(t, handler)
+
+	//Act
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", `{"command":"look","args":[]}`)
+
+	//Assert
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", contentType)
+	}
+	if !strings.Contains(recorder.Body.String(), "You are in break-room") {
+		t.Errorf("Expected the room description, got %s", recorder.Body.String())
+	}
+}
+
+func TestCommandOnFinishedGameIsConflict(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	session := createTestSession(t, handler)
+	path := "/api/v1/sessions/" + session.ID + "/commands"
+
+	//Act
+	exit := performRequest(handler, http.MethodPost, path, `{"command":"exit","args":[]}`)
+	recorder := performRequest(handler, http.MethodPost, path, `{"command":"look","args":[]}`)
+
+	//Assert
+	if exit.Code != http.StatusOK || !strings.Contains(exit.Body.String(), `"game_over":true`) {
+		t.Errorf("Expected exit to end the game, got %d %s", exit.Code, exit.Body.String())
+	}
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	if code := decodeError(t, recorder).Code; code != errorGameOver {
+		t.Errorf("Expected error code %s, got %s", errorGameOver, code)
+	}
+}
+
+func TestUnknownSessionIsNotFound(t *testing.T) {
+	handler := newServer().handler()
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/v1/sessions/missing", ""},
+		{http.MethodDelete, "/api/v1/sessions/missing", ""},
+		{http.MethodPost, "/api/v1/sessions/missing/commands", `{"command":"look"}`},
+		{http.MethodGet, "/api/v1/sessions/missing/actions?command=take", ""},
+	}
+
+	for _, r := range requests {
+		recorder := performRequest(handler, r.method, r.path, r.body)
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected status %d, got %d", r.method, r.path, http.StatusNotFound, recorder.Code)
+			continue
+		}
+		if code := decodeError(t, recorder).Code; code != errorSessionNotFound {
+			t.Errorf("%s %s: expected error code %s, got %s", r.method, r.path, errorSessionNotFound, code)
+		}
+	}
+}
+
+func TestInvalidCommandBodyIsBadRequest(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	session := createTestSession(t, handler)
+	path := "/api/v1/sessions/" + session.ID + "/commands"
+
+	for _, body := range []string{"not json", `{"command":""}`, `{"args":["tea"]}`} {
+		//Act
+		recorder := performRequest(handler, http.MethodPost, path, body)
+
+		//Assert
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Body %q: expected status %d, got %d", body, http.StatusBadRequest, recorder.Code)
+			continue
+		}
+		if code := decodeError(t, recorder).Code; code != errorInvalidInput {
+			t.Errorf("Body %q: expected error code %s, got %s", body, errorInvalidInput, code)
+		}
+	}
+}
+
+func TestWrongMethodIsNotAllowed(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+
+	//Act
+	recorder := performRequest(handler, http.MethodGet, "/api/v1/sessions", "")
+
+	//Assert
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status %d, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+	if allow := recorder.Header().Get("Allow"); allow != http.MethodPost {
+		t.Errorf("Expected Allow header %q, got %q", http.MethodPost, allow)
+	}
+	if code := decodeError(t, recorder).Code; code != errorMethodNotAllowed {
+		t.Errorf("Expected error code %s, got %s", errorMethodNotAllowed, code)
+	}
+}
+
+func TestGetSessionAndActions(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	session := createTestSession(t, handler)
+
+	//Act
+	view := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID, "")
+	actions := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID+"/actions?command=approach", "")
+	missing := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID+"/actions", "")
+
+	//Assert
+	var sessionView SessionView
+	if err := json.NewDecoder(view.Body).Decode(&sessionView); err != nil {
+		t.Fatal(err)
+	}
+	if sessionView.Room != "break-room" || sessionView.GameOver {
+		t.Errorf("Expected an ongoing game in break-room, got %+v", sessionView)
+	}
+	if actions.Code != http.StatusOK || !strings.Contains(actions.Body.String(), "rosie") {
+		t.Errorf("Expected approach actions to include rosie, got %d %s", actions.Code, actions.Body.String())
+	}
+	if missing.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without a command, got %d", http.StatusBadRequest, missing.Code)
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	session := createTestSession(t, handler)
+
+	//Act
+	deleted := performRequest(handler, http.MethodDelete, "/api/v1/sessions/"+session.ID, "")
+	recorder := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID, "")
+
+	//Assert
+	if deleted.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, deleted.Code)
+	}
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after deletion, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func runAs(handler http.Handler, session SessionCreated, body string) *httptest.ResponseRecorder {
+	return performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", body)
+}
+
+func responseMessage(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var response model.GameResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	return response.Message
+}
+
+func TestSayReachesOnlyPlayersInTheRoom(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+	linus := joinWorld(t, handler, ada.WorldID, "Linus")
+	for _, command := range []string{
+		`{"command":"approach","args":["kettle"]}`,
+		`{"command":"take","args":["tea"]}`,
+		`{"command":"approach","args":["rosie"]}`,
+		`{"command":"use","args":["tea"]}`,
+		`{"command":"take","args":["lanyard"]}`,
+		`{"command":"move","args":["south"]}`,
+	} {
+		runAs(handler, linus, command)
+	}
+
+	//Act
+	said := responseMessage(t, runAs(handler, ada, `{"command":"say","args":["the","kettle","works"]}`))
+	heardByGrace := responseMessage(t, runAs(handler, grace, `{"command":"inventory"}`))
+	heardByLinus := responseMessage(t, runAs(handler, linus, `{"command":"inventory"}`))
+
+	//Assert
+	if said != "You say: the kettle works\n" {
+		t.Errorf("Expected the speaker to hear their own words, got %q", said)
+	}
+	if !strings.HasPrefix(heardByGrace, "anonymous says: the kettle works\n\n") {
+		t.Errorf("Expected Grace to hear Ada first, got %q", heardByGrace)
+	}
+	if strings.Contains(heardByLinus, "kettle works") {
+		t.Errorf("Expected Linus in another room not to hear Ada, got %q", heardByLinus)
+	}
+}
+
+func TestShoutReachesEveryRoomOnce(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+	runAs(handler, ada, `{"command":"shout","args":["help!"]}`)
+
+	//Act
+	first := responseMessage(t, runAs(handler, grace, `{"command":"inventory"}`))
+	second := responseMessage(t, runAs(handler, grace, `{"command":"inventory"}`))
+
+	//Assert
+	if !strings.HasPrefix(first, "anonymous shouts: help!\n\n") {
+		t.Errorf("Expected Grace to hear the shout, got %q", first)
+	}
+	if strings.Contains(second, "help!") {
+		t.Errorf("Expected the shout to be heard only once, got %q", second)
+	}
+}
+
+func TestWhisperIsSeenOnlyByItsTarget(t *testing.T) {
+	//Arrange
+	s := newServer()
+	handler := s.handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+	linus := joinWorld(t, handler, ada.WorldID, "Linus")
+
+	//Act
+	runAs(handler, ada, `{"command":"whisper","args":["Grace","the","password","is","nine","letters"]}`)
+	heardByGrace := responseMessage(t, runAs(handler, grace, `{"command":"inventory"}`))
+	heardByLinus := responseMessage(t, runAs(handler, linus, `{"command":"inventory"}`))
+
+	//Assert
+	if !strings.HasPrefix(heardByGrace, "anonymous whispers: the password is nine letters\n\n") {
+		t.Errorf("Expected Grace to hear the whisper, got %q", heardByGrace)
+	}
+	if strings.Contains(heardByLinus, "password") {
+		t.Errorf("Expected Linus not to hear the whisper, got %q", heardByLinus)
+	}
+	session, _ := s.sessions.Get(linus.ID)
+	_, replay, _ := session.Events.Subscribe(0)
+	for _, event := range replay {
+		if strings.Contains(strings.Join(event.Value.Args, " ")+event.Value.Message, "password") {
+			t.Errorf("Expected Linus's event stream not to carry the whisper, got %+v", event.Value)
+		}
+	}
+}
+
+func TestChatCanBeDisabledForAWorld(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"player_name":"Ada","disable_chat":true}`)
+	var ada SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&ada)
+
+	//Act
+	said := responseMessage(t, runAs(handler, ada, `{"command":"say","args":["hello"]}`))
+	joined := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"world_id":"`+ada.WorldID+`","disable_chat":true}`)
+
+	//Assert
+	if said != "Chat is disabled in this world." {
+		t.Errorf("Expected chat to be disabled, got %q", said)
+	}
+	if joined.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d when disabling chat on join, got %d", http.StatusBadRequest, joined.Code)
+	}
+}
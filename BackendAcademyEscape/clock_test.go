@@ -0,0 +1,113 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestTimeCommandShowsTheClock(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.RunGame(model.PlayerInput{Command: "approach", Args: []string{"kettle"}})
+	game.RunGame(model.PlayerInput{Command: "take", Args: []string{"tea"}})
+
+	//Act
+	response := game.RunGame(model.PlayerInput{Command: "time"})
+
+	//Assert
+	expected := "It's 09:04, turn 2.\nThe building locks down at 16:00.\n"
+	if response.Message != expected {
+		t.Errorf("Expected %q, got %q", expected, response.Message)
+	}
+	if clock := game.Clock(); clock.Turn != 2 || clock.String() != "09:04" {
+		t.Errorf("Expected asking the time not to take a turn, got turn %d at %s", clock.Turn, clock)
+	}
+}
+
+func TestCommandCostsCanBeChanged(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetCommandCost("look", 30)
+
+	//Act
+	game.RunGame(model.PlayerInput{Command: "look"})
+	game.RunGame(model.PlayerInput{Command: "no-such-command"})
+
+	//Assert
+	if clock := game.Clock(); clock.Turn != 1 || clock.String() != "09:30" {
+		t.Errorf("Expected only the look to take time, got turn %d at %s", clock.Turn, clock)
+	}
+}
+
+func TestScheduledEventHappensOnceAndReachesEveryPlayer(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	ada, _ := game.Join("Ada")
+	grace, _ := game.Join("Grace")
+	runs := 0
+	game.Schedule(&model.ScheduledEvent{Name: "fire-drill", AtTurn: 2, Run: func(*model.Game) string {
+		runs++
+		return "The fire alarm goes off."
+	}})
+
+	//Act
+	game.RunGameAs(ada, model.PlayerInput{Command: "look"})
+	second := game.RunGameAs(ada, model.PlayerInput{Command: "look"})
+	third := game.RunGameAs(grace, model.PlayerInput{Command: "inventory"})
+
+	//Assert
+	if runs != 1 {
+		t.Errorf("Expected the event to run once, got %d", runs)
+	}
+	if !strings.HasSuffix(second.Message, "\nThe fire alarm goes off.\n") {
+		t.Errorf("Expected Ada to be told on the turn it happened, got %q", second.Message)
+	}
+	if !strings.HasPrefix(third.Message, "The fire alarm goes off.\n\n") {
+		t.Errorf("Expected Grace to be told first thing, got %q", third.Message)
+	}
+}
+
+func TestBuildingLocksDownAtFourPM(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetCommandCost("look", 7*60)
+
+	//Act
+	response := game.RunGame(model.PlayerInput{Command: "look"})
+
+	//Assert
+	if !response.GameOver || !game.IsOver() {
+		t.Errorf("Expected the game to end at 16:00")
+	}
+	if !strings.Contains(response.Message, "It's 16:00. The shutters come down") {
+		t.Errorf("Expected the lockdown message, got %q", response.Message)
+	}
+}
+
+func TestTeaGoesColdAfterBrewing(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.RunGame(model.PlayerInput{Command: "approach", Args: []string{"kettle"}})
+	game.RunGame(model.PlayerInput{Command: "take", Args: []string{"tea"}})
+
+	//Act
+	var messages []string
+	for i := 0; i < 15; i++ {
+		messages = append(messages, game.RunGame(model.PlayerInput{Command: "inventory"}).Message)
+	}
+
+	//Assert
+	last := messages[len(messages)-1]
+	if !strings.Contains(last, "going cold") {
+		t.Errorf("Expected the tea to have gone cold, got %q", last)
+	}
+	if strings.Contains(strings.Join(messages[:len(messages)-2], ""), "The tea is going cold.") {
+		t.Errorf("Expected the tea to cool only after 15 turns")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// These tests are most useful under the race detector: go test -race ./...
+
+var stormCommands = []string{
+	`{"command":"look"}`,
+	`{"command":"approach","args":["kettle"]}`,
+	`{"command":"take","args":["tea"]}`,
+	`{"command":"drop","args":["tea"]}`,
+	`{"command":"inventory"}`,
+	`{"command":"approach","args":["rosie"]}`,
+	`{"command":"use","args":["tea"]}`,
+	`{"command":"leave"}`,
+	`{"command":"map"}`,
+	`{"command":"shout","args":["hello"]}`,
+}
+
+func TestCommandStormOnOneSession(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	session := createTestSession(t, handler)
+	path := "/api/v1/sessions/" + session.ID
+	stored, _ := s.sessions.Get(session.ID)
+	subscriber, _, _ := stored.Outbox.Subscribe(0)
+
+	//Act
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j, command := range stormCommands {
+				performRequest(handler, http.MethodPost, path+"/commands", command)
+				switch (i + j) % 4 {
+				case 0:
+					performRequest(handler, http.MethodGet, path+"/actions?command=take", "")
+				case 1:
+					performRequest(handler, http.MethodGet, path, "")
+				case 2:
+					facilitatorRequest(handler, http.MethodGet, "/api/v1/facilitator/sessions", "", "secret")
+				case 3:
+					facilitatorRequest(handler, http.MethodPost, "/api/v1/facilitator/sessions/"+session.ID+"/messages", `{"message":"Keep going."}`, "secret")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	//Assert
+	transcript := stored.Transcript(0)
+	for i, entry := range transcript {
+		if entry.Seq != i+1 {
+			t.Fatalf("Expected transcript entry %d to have seq %d, got %d", i, i+1, entry.Seq)
+		}
+	}
+	var last int64
+	for len(subscriber) > 0 {
+		frame := <-subscriber
+		if frame.Seq <= last {
+			t.Fatalf("Expected frame seqs to increase, got %d after %d", frame.Seq, last)
+		}
+		last = frame.Seq
+	}
+}
+
+func TestCommandStormInASharedWorld(t *testing.T) {
+	//Arrange
+	s := newServer()
+	handler := s.handler()
+	first := createTestSession(t, handler)
+	sessions := []SessionCreated{first}
+	for i := 1; i < 8; i++ {
+		sessions = append(sessions, joinWorld(t, handler, first.WorldID, fmt.Sprintf("player%d", i)))
+	}
+
+	//Act
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < 5; round++ {
+				for _, command := range stormCommands {
+					runAs(handler, session, command)
+					performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID+"/actions?command=give", "")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	//Assert
+	teaHolders := 0
+	for _, session := range sessions {
+		stored, _ := s.sessions.Get(session.ID)
+		actions, err := stored.Game.GetAvailableActionsFor(stored.PlayerID, "drop")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range actions.Actions {
+			if item == "tea" {
+				teaHolders++
+			}
+		}
+	}
+	if teaHolders > 1 {
+		t.Errorf("Expected at most 1 player to hold the tea, got %d", teaHolders)
+	}
+}
+
+func TestModelIsSafeForConcurrentUse(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	var players []string
+	for i := 0; i < 4; i++ {
+		id, _ := game.Join("")
+		players = append(players, id)
+	}
+	stop := game.OnEvent(func(model.GameEvent) {})
+
+	//Act
+	var wg sync.WaitGroup
+	for _, id := range players {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				game.RunGameAs(id, model.PlayerInput{Command: "approach", Args: []string{"kettle"}})
+				game.RunGameAs(id, model.PlayerInput{Command: "take", Args: []string{"tea"}})
+				game.RunGameAs(id, model.PlayerInput{Command: "give", Args: []string{"tea", "to", "rosie"}})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				game.GetAvailableActionsFor(id, "take")
+				game.Progress(id)
+				game.IsOverFor(id)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				game.OnEvent(func(model.GameEvent) {})()
+			}
+		}()
+	}
+	wg.Wait()
+	stop()
+
+	//Assert
+	progress, _ := game.Progress(players[0])
+	if len(progress.TriggeredEvents) != 1 || progress.TriggeredEvents[0] != "get-your-lanyard" {
+		t.Errorf("Expected the tea to reach rosie exactly once, got %v", progress.TriggeredEvents)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+// unlockAlansComputer plays until the desk and the dishwasher are revealed.
+func unlockAlansComputer(game *model.Game) {
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	run(game, "move", "south")
+	run(game, "approach", "computer")
+	run(game, "iiwsccrtc")
+	run(game, "approach", "desk")
+}
+
+func TestPlatesComeOffTheStackFromTheTop(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	unlockAlansComputer(game)
+
+	//Act
+	top := run(game, "take", "first-plate")
+	dropped := run(game, "drop", "first-plate")
+	underneath := run(game, "take", "third-plate", "from", "stack")
+
+	//Assert
+	if !strings.HasPrefix(top.Message, "first-plate has been added to your inventory.\n") {
+		t.Errorf("Expected to take the top plate, got %q", top.Message)
+	}
+	if !strings.HasPrefix(dropped.Message, "You can't just leave those plates lying around!") {
+		t.Errorf("Expected plates not to be dropped, got %q", dropped.Message)
+	}
+	if !strings.HasPrefix(underneath.Message, "As you attempt to grab the greasy plates") || !underneath.GameOver {
+		t.Errorf("Expected the stack to fall and the game to end, got %q", underneath.Message)
+	}
+}
+
+func TestLoadingTheDishwasherWinsThePlateChallenge(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	unlockAlansComputer(game)
+	plates := []string{"first-plate", "second-plate", "third-plate", "fourth-plate", "fifth-plate", "sixth-plate"}
+
+	//Act
+	var loaded, won model.GameResponse
+	for trip := 0; trip < 2; trip++ {
+		for _, plate := range plates[trip*3 : trip*3+3] {
+			run(game, "take", plate)
+		}
+		run(game, "move", "north")
+		for _, plate := range plates[trip*3 : trip*3+3] {
+			loaded = run(game, "put", plate, "in", "dishwasher")
+		}
+		won = run(game, "move", "south")
+	}
+
+	//Assert
+	if !strings.HasPrefix(loaded.Message, "You loaded the sixth plate into the dishwasher.") {
+		t.Errorf("Expected the last plate to be loaded, got %q", loaded.Message)
+	}
+	if !strings.HasPrefix(won.Message, "You load the dirty plates into the dishwasher and switch it on") {
+		t.Errorf("Expected the plate challenge to be won, got %q", won.Message)
+	}
+}
+
+func TestReloadingAPlateDoesNotLoadItAgain(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	unlockAlansComputer(game)
+	run(game, "take", "first-plate")
+	run(game, "move", "north")
+	first := run(game, "put", "first-plate", "in", "dishwasher")
+	run(game, "take", "first-plate", "from", "dishwasher")
+
+	//Act
+	again := run(game, "put", "first-plate", "in", "dishwasher")
+
+	//Assert
+	if !strings.HasPrefix(first.Message, "You loaded the first plate into the dishwasher.") {
+		t.Errorf("Expected the first load to count, got %q", first.Message)
+	}
+	if again.Message != "You put first-plate in dishwasher.\n" {
+		t.Errorf("Expected the plate to just go back in, got %q", again.Message)
+	}
+}
+
+func TestContainersHoldItemsInOrderAndCarryTheirWeight(t *testing.T) {
+	//Arrange
+	drawer := &model.Container{Name: "drawer", Description: "A desk drawer.", Order: model.Queue, Closable: true, Closed: true, Items: []*model.Item{
+		{Name: "pen", Description: "A pen.", Weight: 1},
+		{Name: "stapler", Description: "A stapler.", Weight: 3},
+	}}
+	bag := &model.Item{Name: "bag", Description: "A tote bag.", Weight: 1, Container: &model.Container{Name: "bag", Capacity: 1}}
+	room := model.Room{Name: "Room 1", Items: map[string]*model.Item{}, Containers: map[string]*model.Container{"drawer": drawer}}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{"bag": bag}, CarriedWeight: 1, AvailableWeight: 19}
+
+	//Act
+	closed := player.TakeFrom("pen", "drawer", model.ConsoleDisplay{})
+	player.Open("drawer", model.ConsoleDisplay{})
+	behind := player.TakeFrom("stapler", "drawer", model.ConsoleDisplay{})
+	player.TakeFrom("pen", "drawer", model.ConsoleDisplay{})
+	player.TakeFrom("stapler", "drawer", model.ConsoleDisplay{})
+	player.PutIn("stapler", "bag", model.ConsoleDisplay{})
+	full := player.PutIn("pen", "bag", model.ConsoleDisplay{})
+	weightWithBag := player.CarriedWeight
+	player.Drop("bag", model.ConsoleDisplay{})
+
+	//Assert
+	if closed != "drawer is closed.\n" {
+		t.Errorf("Expected the closed drawer to keep its items, got %q", closed)
+	}
+	if behind != "You can't get stapler out of drawer without moving other things first.\n" {
+		t.Errorf("Expected the drawer to let the pen out first, got %q", behind)
+	}
+	if full != "bag is full.\n" {
+		t.Errorf("Expected the bag to be full, got %q", full)
+	}
+	if weightWithBag != 5 {
+		t.Errorf("Expected to carry 5 with the stapler in the bag, got %d", weightWithBag)
+	}
+	if player.CarriedWeight != 1 || player.AvailableWeight != 19 {
+		t.Errorf("Expected to carry only the pen after dropping the bag, got %d and %d available", player.CarriedWeight, player.AvailableWeight)
+	}
+}
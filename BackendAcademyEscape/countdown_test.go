@@ -0,0 +1,156 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponsesShowTheTimeLeftInTimedWorlds(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"time_limit_seconds":600}`)
+	var timed SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&timed)
+	untimed := createTestSession(t, handler)
+
+	//Act
+	var timedResponse, untimedResponse model.GameResponse
+	json.NewDecoder(runAs(handler, timed, `{"command":"look"}`).Body).Decode(&timedResponse)
+	json.NewDecoder(runAs(handler, untimed, `{"command":"look"}`).Body).Decode(&untimedResponse)
+
+	//Assert
+	if timedResponse.RemainingSeconds == nil || *timedResponse.RemainingSeconds > 600 || *timedResponse.RemainingSeconds < 590 {
+		t.Errorf("Expected about 600 seconds left, got %v", timedResponse.RemainingSeconds)
+	}
+	if untimedResponse.RemainingSeconds != nil {
+		t.Errorf("Expected no time left without a time limit, got %d", *untimedResponse.RemainingSeconds)
+	}
+}
+
+func TestCountdownEndsTheGameOnTheNextCommand(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetTimeLimit(10 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	//Act
+	response := game.RunGame(model.PlayerInput{Command: "look"})
+
+	//Assert
+	if !response.GameOver || !strings.HasPrefix(response.Message, "Time's up!") {
+		t.Errorf("Expected the game to have timed out, got %+v", response)
+	}
+	if response.RemainingSeconds == nil || *response.RemainingSeconds != 0 {
+		t.Errorf("Expected no time left, got %v", response.RemainingSeconds)
+	}
+}
+
+func TestPausedCountdownDoesNotRunOut(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetTimeLimit(20 * time.Millisecond)
+	game.PauseCountdown()
+
+	//Act
+	time.Sleep(40 * time.Millisecond)
+	timedOut := game.CheckTimeout()
+
+	//Assert
+	if timedOut || game.IsOver() {
+		t.Errorf("Expected a paused countdown not to run out")
+	}
+}
+
+func TestCountdownIsPushedToWebSockets(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	t.Cleanup(testServer.Close)
+	session, _ := s.sessions.Create("tester", "", WorldOptions{TimeLimit: 50 * time.Millisecond})
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	frame := readFrame(t, conn)
+
+	//Assert
+	if frame.Type != frameNotification || !frame.GameOver || !strings.HasPrefix(frame.Message, "Time's up!") {
+		t.Errorf("Expected a game over notification, got %+v", frame)
+	}
+}
+
+func TestCountdownIsPushedInThePlayersLanguage(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	t.Cleanup(testServer.Close)
+	session, _ := s.sessions.Create("tester", "", WorldOptions{TimeLimit: 50 * time.Millisecond})
+	session.Game.SetLocale(session.PlayerID, "fr")
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	frame := readFrame(t, conn)
+
+	//Assert
+	if frame.Type != frameNotification || !frame.GameOver || frame.Message != "Le temps est écoulé ! Les portes restent verrouillées et le hack day est terminé." {
+		t.Errorf("Expected the game over notification in French, got %+v", frame)
+	}
+}
+
+func TestFacilitatorControlsTheCountdown(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"time_limit_seconds":60}`)
+	var session SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&session)
+	path := "/api/v1/facilitator/worlds/" + session.WorldID + "/countdown"
+
+	//Act
+	var paused, extended, reset CountdownView
+	json.NewDecoder(facilitatorRequest(handler, http.MethodPost, path, `{"action":"pause"}`, "secret").Body).Decode(&paused)
+	json.NewDecoder(facilitatorRequest(handler, http.MethodPost, path, `{"action":"extend","seconds":30}`, "secret").Body).Decode(&extended)
+	json.NewDecoder(facilitatorRequest(handler, http.MethodPost, path, `{"action":"reset"}`, "secret").Body).Decode(&reset)
+	invalid := facilitatorRequest(handler, http.MethodPost, path, `{"action":"stop"}`, "secret")
+
+	//Assert
+	if !paused.Paused || paused.RemainingSeconds > 60 {
+		t.Errorf("Expected a paused countdown with at most 60 seconds left, got %+v", paused)
+	}
+	if extended.LimitSeconds != 90 || extended.RemainingSeconds != paused.RemainingSeconds+30 {
+		t.Errorf("Expected 30 more seconds, got %+v after %+v", extended, paused)
+	}
+	if !reset.Paused || reset.RemainingSeconds != 90 {
+		t.Errorf("Expected the full 90 seconds back, still paused, got %+v", reset)
+	}
+	if invalid.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown action, got %d", http.StatusBadRequest, invalid.Code)
+	}
+}
+
+func TestCountdownChangesNeedATimedWorld(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	session := createTestSession(t, handler)
+
+	//Act
+	untimed := facilitatorRequest(handler, http.MethodPost, "/api/v1/facilitator/worlds/"+session.WorldID+"/countdown", `{"action":"pause"}`, "secret")
+	missing := facilitatorRequest(handler, http.MethodPost, "/api/v1/facilitator/worlds/missing/countdown", `{"action":"pause"}`, "secret")
+
+	//Assert
+	if untimed.Code != http.StatusConflict || decodeError(t, untimed).Code != errorNoCountdown {
+		t.Errorf("Expected %d %s, got %d", http.StatusConflict, errorNoCountdown, untimed.Code)
+	}
+	if missing.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, missing.Code)
+	}
+}
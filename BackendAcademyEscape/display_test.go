@@ -0,0 +1,123 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const markedUpRoom = "{h}You are in Room 1{/h}\n\nThis is room 1.\n\n{h}You can approach:{/h}\n{li}{entity}rosie{/entity}{/li}\n{li}{entity}cat{/entity} (currently approached){/li}\n"
+
+func TestPlainDisplayDropsTheMarkup(t *testing.T) {
+	//Arrange
+	display := model.PlainDisplay{}
+
+	//Act
+	plain := display.Show(markedUpRoom + "say {lbrace}h} and {unknown}")
+
+	//Assert
+	expected := "You are in Room 1\n\nThis is room 1.\n\nYou can approach:\n- rosie\n- cat (currently approached)\nsay {h} and {unknown}"
+	if plain != expected {
+		t.Errorf("Expected %q, got %q", expected, plain)
+	}
+}
+
+func TestHTMLDisplayMarksUpHeadingsListsAndLinks(t *testing.T) {
+	//Arrange
+	display := model.HTMLDisplay{}
+
+	//Act
+	rendered := display.Show(markedUpRoom + "{code}if pile < 1:{/code}")
+
+	//Assert
+	expected := "<h3>You are in Room 1</h3><br>\nThis is room 1.<br>\n<h3>You can approach:</h3>" +
+		`<ul><li><a class="entity" data-command="approach rosie">rosie</a></li><li><a class="entity" data-command="approach cat">cat</a> (currently approached)</li></ul>` +
+		"<pre><code>if pile &lt; 1:</code></pre>"
+	if rendered != expected {
+		t.Errorf("Expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestJSONDisplayGroupsListItems(t *testing.T) {
+	//Arrange
+	display := model.JSONDisplay{}
+
+	//Act
+	var nodes []model.Node
+	err := json.Unmarshal([]byte(display.Show(markedUpRoom)), &nodes)
+
+	//Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 6 || nodes[0].Type != model.HeadingNode || nodes[4].Type != model.ListNode {
+		t.Fatalf("Expected headings, text and a list, got %+v", nodes)
+	}
+	list := nodes[4]
+	if len(list.Children) != 2 || list.Children[1].Children[0].Type != model.EntityNode || list.Children[1].Children[0].Children[0].Text != "cat" {
+		t.Errorf("Expected two list items naming entities, got %+v", list)
+	}
+}
+
+func TestANSIDisplayRestoresTheOuterStyle(t *testing.T) {
+	//Arrange
+	display := model.ANSIDisplay{}
+
+	//Act
+	rendered := display.Show("{h}Room with {item}tea{/item}{/h}")
+
+	//Assert
+	expected := "\x1b[1mRoom with \x1b[1m\x1b[36mtea\x1b[0m\x1b[1m\x1b[0m"
+	if rendered != expected {
+		t.Errorf("Expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestSessionsRenderResponsesInTheirFormat(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"format": "html"}`)
+	var created SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&created)
+
+	//Act
+	look := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+created.ID+"/commands", `{"command": "look"}`)
+	invalid := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"format": "rtf"}`)
+
+	//Assert
+	var response model.GameResponse
+	json.NewDecoder(look.Body).Decode(&response)
+	if !strings.HasPrefix(response.Message, "<h3>You are in break-room</h3>") || !strings.Contains(response.Message, `<a class="entity" data-command="approach rosie">rosie</a>`) {
+		t.Errorf("Expected the room as HTML, got %q", response.Message)
+	}
+	if invalid.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown format, got %d", http.StatusBadRequest, invalid.Code)
+	}
+}
+
+func TestTypedTextIsNeverTakenForMarkup(t *testing.T) {
+	for name := range model.Displays {
+		//Arrange
+		game := &model.Game{}
+		game.SetupWorld()
+		id, _ := game.Join("Sam")
+		game.SetDisplay(id, name)
+		game.TellPlayer(id, "try {code}")
+
+		//Act
+		response := game.RunGameAs(id, model.PlayerInput{Command: "take", Args: []string{"{code}tea"}})
+
+		//Assert
+		if !strings.Contains(response.Message, "try {code}") || !strings.Contains(response.Message, "{code}tea") {
+			t.Errorf("%s: expected the typed text as it was typed, got %q", name, response.Message)
+		}
+		if strings.Contains(response.Message, "<code>") || strings.Contains(response.Message, `"code"`) {
+			t.Errorf("%s: expected no code block, got %q", name, response.Message)
+		}
+		if name == "json" && !json.Valid([]byte(response.Message)) {
+			t.Errorf("json: expected valid JSON, got %q", response.Message)
+		}
+	}
+}
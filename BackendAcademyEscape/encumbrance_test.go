@@ -0,0 +1,122 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestHandsFillUpUntilSomethingIsEquipped(t *testing.T) {
+	//Arrange
+	lanyard := &model.Item{Name: "lanyard", Description: "A lanyard.", Weight: 1, Wearable: true}
+	mug := &model.Item{Name: "mug", Description: "A mug.", Weight: 1}
+	room := model.Room{Name: "Room 1", Items: map[string]*model.Item{"mug": mug}}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{"lanyard": lanyard}, CarriedWeight: 1, AvailableWeight: 19,
+		Capacity: model.Limits{Hands: 1}}
+
+	//Act
+	full := player.Take("mug", model.ConsoleDisplay{})
+	equipped := player.Equip("lanyard", model.ConsoleDisplay{})
+	taken := player.Take("mug", model.ConsoleDisplay{})
+	unequipped := player.Unequip("lanyard", model.ConsoleDisplay{})
+	inventory := player.ShowInventory(model.ConsoleDisplay{})
+
+	//Assert
+	if !strings.HasPrefix(full, "Your hands are full.") {
+		t.Errorf("Expected the mug not to fit in full hands, got %q", full)
+	}
+	if equipped != "You put on lanyard.\n" {
+		t.Errorf("Expected to put on the lanyard, got %q", equipped)
+	}
+	if taken != "mug has been added to your inventory.\n" {
+		t.Errorf("Expected the mug to fit once the lanyard was worn, got %q", taken)
+	}
+	if !strings.HasPrefix(unequipped, "Your hands are full.") {
+		t.Errorf("Expected the lanyard to stay on with no hand free, got %q", unequipped)
+	}
+	if !strings.HasPrefix(inventory, "Carrying: hands 1/1\n") || !strings.Contains(inventory, "(worn)") {
+		t.Errorf("Expected the hands in use and the worn lanyard in the inventory, got %q", inventory)
+	}
+}
+
+func TestTakingOutOfACarriedBagStillNeedsAHand(t *testing.T) {
+	//Arrange
+	mug := &model.Item{Name: "mug", Description: "A mug.", Weight: 2}
+	bag := &model.Item{Name: "bag", Description: "A tote bag.", Weight: 1, Wearable: true, Container: &model.Container{Name: "bag", Items: []*model.Item{mug}}}
+	room := model.Room{Name: "Room 1"}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{"bag": bag}, CarriedWeight: 3, AvailableWeight: 17,
+		Capacity: model.Limits{Weight: 3, Hands: 1}}
+
+	//Act
+	full := player.TakeFrom("mug", "bag", model.ConsoleDisplay{})
+	player.Equip("bag", model.ConsoleDisplay{})
+	taken := player.TakeFrom("mug", "bag", model.ConsoleDisplay{})
+	inventory := player.ShowInventory(model.ConsoleDisplay{})
+
+	//Assert
+	if !strings.HasPrefix(full, "Your hands are full.") {
+		t.Errorf("Expected the mug to need a free hand, got %q", full)
+	}
+	if taken != "mug has been added to your inventory.\n" {
+		t.Errorf("Expected the mug's weight not to be counted twice, got %q", taken)
+	}
+	if !strings.HasPrefix(inventory, "Carrying: weight 3/3, hands 1/1\n") {
+		t.Errorf("Expected the same weight and one hand in use, got %q", inventory)
+	}
+}
+
+func TestVolumeLimitExplainsWhatDoesNotFit(t *testing.T) {
+	//Arrange
+	box := &model.Item{Name: "box", Description: "A big box.", Weight: 1, Volume: 4}
+	room := model.Room{Name: "Room 1", Items: map[string]*model.Item{"box": box}}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{}, AvailableWeight: 20,
+		Capacity: model.Limits{Weight: 20, Volume: 3}}
+
+	//Act
+	taken := player.Take("box", model.ConsoleDisplay{})
+	inventory := player.ShowInventory(model.ConsoleDisplay{})
+
+	//Assert
+	if taken != "box won't fit: it takes 4 space and you have 3 left.\n" {
+		t.Errorf("Expected the box to be too big, got %q", taken)
+	}
+	if inventory != "Your inventory is empty.\nCarrying: weight 0/20, volume 0/3\n" {
+		t.Errorf("Expected the limits in the inventory, got %q", inventory)
+	}
+}
+
+func TestEncumbranceAppliesToEveryPlayer(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetEncumbrance(model.Limits{Weight: 20, Hands: 1})
+	addFridge(game)
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+	run(game, "leave")
+	run(game, "open", "fridge")
+
+	//Act
+	taken := run(game, "take", "milk", "from", "fridge")
+
+	//Assert
+	if !strings.HasPrefix(taken.Message, "Your hands are full.") {
+		t.Errorf("Expected no free hand for the milk, got %q", taken.Message)
+	}
+}
+
+func TestDefaultEncumbranceIsTheWeightLimit(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+
+	//Act
+	inventory := run(game, "inventory")
+
+	//Assert
+	if !strings.HasPrefix(inventory.Message, "Available space: 18\nYour inventory contains:\n") {
+		t.Errorf("Expected the usual weight summary, got %q", inventory.Message)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+// addCupboard puts a cupboard in the break room with a packet of biscuits
+// stashed behind the mugs, which Rosie notices being found.
+func addCupboard(game *model.Game) {
+	game.AddItem("break-room", &model.Item{Name: "biscuits", Description: "A half-eaten packet of chocolate digestives.", Weight: 1, Hidden: true})
+	game.AddFeature("break-room", "cupboard", &model.Detail{
+		First:    "You open the cupboard above the sink. Behind the mugs, somebody has stashed a packet of biscuits.\n",
+		Again:    "Mugs, teabags and not much else.\n",
+		Reveals:  []string{"biscuits"},
+		Triggers: &model.Event{Description: "found-the-biscuits", Outcome: "Rosie glances over. \"Those are the good biscuits. Don't let Alan see.\"\n"},
+	})
+}
+
+func TestExaminingTheCupboardRevealsTheBiscuitsOnce(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addCupboard(game)
+
+	//Act
+	first := run(game, "examine", "cupboard")
+	again := run(game, "examine", "cupboard")
+	taken := run(game, "take", "biscuits")
+
+	//Assert
+	if !strings.Contains(first.Message, "(biscuits can now be found in the room)") || !strings.Contains(first.Message, "Those are the good biscuits.") {
+		t.Errorf("Expected the biscuits to be revealed and Rosie to notice, got %q", first.Message)
+	}
+	if again.Message != "Mugs, teabags and not much else.\n" {
+		t.Errorf("Expected the repeat description, got %q", again.Message)
+	}
+	if !strings.HasPrefix(taken.Message, "biscuits has been added to your inventory.\n") {
+		t.Errorf("Expected to take the biscuits, got %q", taken.Message)
+	}
+}
+
+func TestExaminingIsFirstTimeForEachPlayer(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	ada, _ := game.Join("Ada")
+	grace, _ := game.Join("Grace")
+	game.RunGameAs(ada, model.PlayerInput{Command: "examine", Args: []string{"cat"}})
+
+	//Act
+	byAda := game.RunGameAs(ada, model.PlayerInput{Command: "examine", Args: []string{"cat"}})
+	byGrace := game.RunGameAs(grace, model.PlayerInput{Command: "examine", Args: []string{"cat"}})
+
+	//Assert
+	if !strings.HasPrefix(byAda.Message, "The cat yawns.") {
+		t.Errorf("Expected Ada to see the repeat description, got %q", byAda.Message)
+	}
+	if !strings.HasPrefix(byGrace.Message, "The cat's name tag reads") {
+		t.Errorf("Expected Grace to see the first description, got %q", byGrace.Message)
+	}
+}
+
+func TestExaminedDescriptionsChangeAfterEvents(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	before := run(game, "examine", "rosie")
+
+	//Act
+	giveRosieTea(game)
+	after := run(game, "examine", "rosie")
+
+	//Assert
+	if !strings.HasPrefix(before.Message, "Rosie is slumped in a chair") {
+		t.Errorf("Expected the sleepy description, got %q", before.Message)
+	}
+	if !strings.HasPrefix(after.Message, "Rosie sips the tea") {
+		t.Errorf("Expected the description after the tea, got %q", after.Message)
+	}
+}
+
+func TestExaminingExitsAndMissingThings(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+
+	//Act
+	exit := run(game, "examine", "south")
+	missing := run(game, "examine", "unicorn")
+
+	//Assert
+	if exit.Message != "The way south leads to coding-lab, but it's locked.\n" {
+		t.Errorf("Expected the locked exit to be described, got %q", exit.Message)
+	}
+	if missing.Message != "You can't see unicorn here.\n" {
+		t.Errorf("Expected nothing to examine, got %q", missing.Message)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestLanyardOpensTheDoorsForWhoeverCarriesIt(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+
+	//Act
+	shut := run(game, "move", "south")
+	locked := run(game, "map")
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	moved := run(game, "move", "south")
+
+	//Assert
+	if shut.Message != "Doors are shut for you if you don't have a lanyard." {
+		t.Errorf("Expected the door to be shut without a lanyard, got %q", shut.Message)
+	}
+	if !strings.Contains(locked.Message, "south: coding-lab (locked)") {
+		t.Errorf("Expected the map to show the door locked, got %q", locked.Message)
+	}
+	if !strings.HasPrefix(moved.Message, "You are in coding-lab\n") {
+		t.Errorf("Expected the lanyard to open the door, got %q", moved.Message)
+	}
+}
+
+func TestPasswordLockedExitUnlocksForEverybody(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	ada, _ := game.Join("Ada")
+	grace, _ := game.Join("Grace")
+	game.AddExit("break-room", "west", "terminal-room", &model.Exit{Lock: &model.Lock{Password: "letmein", Locked: true}})
+
+	//Act
+	noPassword := game.RunGameAs(ada, model.PlayerInput{Command: "unlock", Args: []string{"west"}})
+	wrong := game.RunGameAs(ada, model.PlayerInput{Command: "unlock", Args: []string{"west", "opensesame"}})
+	blocked := game.RunGameAs(grace, model.PlayerInput{Command: "move", Args: []string{"west"}})
+	unlocked := game.RunGameAs(ada, model.PlayerInput{Command: "unlock", Args: []string{"west", "letmein"}})
+	moved := game.RunGameAs(grace, model.PlayerInput{Command: "move", Args: []string{"west"}})
+
+	//Assert
+	if noPassword.Message != "The way west needs a password (e.g., unlock west <password>).\n" {
+		t.Errorf("Expected to be asked for a password, got %q", noPassword.Message)
+	}
+	if wrong.Message != "That's not the right password.\n" {
+		t.Errorf("Expected the wrong password to be refused, got %q", wrong.Message)
+	}
+	if blocked.Message != "The way west is locked.\n" {
+		t.Errorf("Expected Grace to be stopped by the lock, got %q", blocked.Message)
+	}
+	if unlocked.Message != "You unlock the way west.\n" {
+		t.Errorf("Expected the exit to unlock, got %q", unlocked.Message)
+	}
+	if !strings.HasPrefix(moved.Message, "You are in terminal-room\n") {
+		t.Errorf("Expected Grace to go through the unlocked exit, got %q", moved.Message)
+	}
+}
+
+func TestExitLockedUntilAnEventCanBeLockedAgain(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddExit("break-room", "west", "terminal-room", &model.Exit{
+		Lock:              &model.Lock{Event: "get-your-lanyard", Locked: true},
+		LockedDescription: "The fire door won't budge.\n",
+	})
+
+	//Act
+	early := run(game, "unlock", "west")
+	giveRosieTea(game)
+	run(game, "leave")
+	unlocked := run(game, "unlock", "west")
+	locked := run(game, "lock", "west")
+	blocked := run(game, "move", "west")
+
+	//Assert
+	if early.Message != "The way west won't unlock yet.\n" {
+		t.Errorf("Expected the exit to stay locked before the event, got %q", early.Message)
+	}
+	if unlocked.Message != "You unlock the way west.\n" {
+		t.Errorf("Expected the exit to unlock after the event, got %q", unlocked.Message)
+	}
+	if locked.Message != "You lock the way west.\n" {
+		t.Errorf("Expected the exit to lock again, got %q", locked.Message)
+	}
+	if blocked.Message != "The fire door won't budge.\n" {
+		t.Errorf("Expected the exit's locked description, got %q", blocked.Message)
+	}
+}
+
+func TestHiddenOneWayExitAppearsAfterAnEvent(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddExit("break-room", "west", "terminal-room", &model.Exit{Hidden: true, RevealedBy: "get-your-lanyard", UnlockedDescription: "You slip out through the fire door.\n"})
+
+	//Act
+	hidden := run(game, "move", "west")
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+	run(game, "approach", "rosie")
+	revealed := run(game, "use", "tea")
+	moved := run(game, "move", "west")
+	back := run(game, "move", "east")
+
+	//Assert
+	if hidden.Message != "You can't go that way!\n" {
+		t.Errorf("Expected the hidden exit to be unusable, got %q", hidden.Message)
+	}
+	if !strings.Contains(revealed.Message, "A way west has opened up.") {
+		t.Errorf("Expected to be told about the new exit, got %q", revealed.Message)
+	}
+	if !strings.HasPrefix(moved.Message, "You slip out through the fire door.\nYou are in terminal-room\n") {
+		t.Errorf("Expected to go through the revealed exit, got %q", moved.Message)
+	}
+	if back.Message != "You can't go that way!\n" {
+		t.Errorf("Expected the exit to only go one way, got %q", back.Message)
+	}
+}
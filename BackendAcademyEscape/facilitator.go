@@ -0,0 +1,192 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxFacilitatorMessageLength = 500
+
+type SessionSummary struct {
+	ID                        string    `json:"id"`
+	WorldID                   string    `json:"world_id"`
+	PlayerName                string    `json:"player_name"`
+	Room                      string    `json:"room"`
+	GameOver                  bool      `json:"game_over"`
+	TriggeredEvents           []string  `json:"triggered_events"`
+	RemainingPasswordAttempts int       `json:"remaining_password_attempts"`
+	IdleSeconds               int64     `json:"idle_seconds"`
+	RemainingSeconds          *int      `json:"remaining_seconds,omitempty"`
+	CreatedAt                 time.Time `json:"created_at"`
+}
+
+type SessionList struct {
+	Sessions []SessionSummary `json:"sessions"`
+}
+
+// TranscriptEntry is either a command with the response the player saw, or
+// a message a facilitator sent.
+type TranscriptEntry struct {
+	Seq      int       `json:"seq"`
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	Command  string    `json:"command,omitempty"`
+	Args     []string  `json:"args,omitempty"`
+	Message  string    `json:"message"`
+	GameOver bool      `json:"game_over,omitempty"`
+}
+
+type Transcript struct {
+	Entries []TranscriptEntry `json:"entries"`
+}
+
+type FacilitatorMessage struct {
+	Message string `json:"message"`
+}
+
+const (
+	countdownPause  = "pause"
+	countdownResume = "resume"
+	countdownExtend = "extend"
+	countdownReset  = "reset"
+)
+
+// CountdownChange pauses, resumes, extends or resets a world's time limit.
+// Seconds is how much time to add, and is only used to extend.
+type CountdownChange struct {
+	Action  string `json:"action"`
+	Seconds int    `json:"seconds,omitempty"`
+}
+
+type CountdownView struct {
+	WorldID          string `json:"world_id"`
+	LimitSeconds     int    `json:"limit_seconds"`
+	RemainingSeconds int    `json:"remaining_seconds"`
+	Paused           bool   `json:"paused"`
+}
+
+// facilitatorOnly rejects requests that do not carry the facilitator token
+// as a bearer token. With no token configured the facilitator API is closed.
+func (s *server) facilitatorOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		token, ok := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+		if s.facilitatorToken == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.facilitatorToken)) != 1 {
+			writer.Header().Set("WWW-Authenticate", `Bearer realm="facilitator"`)
+			writeError(writer, http.StatusUnauthorized, errorUnauthorized, "A valid facilitator token is required.")
+			return
+		}
+		handler(writer, request)
+	}
+}
+
+func (s *server) listSessions(writer http.ResponseWriter, request *http.Request) {
+	list := SessionList{Sessions: []SessionSummary{}}
+	for _, session := range s.sessions.List() {
+		list.Sessions = append(list.Sessions, session.Summary())
+	}
+	writeJSON(writer, http.StatusOK, list)
+}
+
+func (s *server) getTranscript(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	after := 0
+	if value := request.URL.Query().Get("after"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(writer, http.StatusBadRequest, errorInvalidInput, "after must be a non-negative integer.")
+			return
+		}
+		after = parsed
+	}
+
+	writeJSON(writer, http.StatusOK, Transcript{Entries: session.Transcript(after)})
+}
+
+func (s *server) sendFacilitatorMessage(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	var body FacilitatorMessage
+	if err := decodeBody(writer, request, &body); err != nil {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, err.Error())
+		return
+	}
+	message := strings.TrimSpace(body.Message)
+	if message == "" || len(message) > maxFacilitatorMessageLength {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, fmt.Sprintf("The message must be between 1 and %d characters.", maxFacilitatorMessageLength))
+		return
+	}
+
+	session.SendFacilitatorMessage(message)
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) changeCountdown(writer http.ResponseWriter, request *http.Request) {
+	id := request.PathValue("id")
+	world, ok := s.sessions.GetWorld(id)
+	if !ok {
+		writeError(writer, http.StatusNotFound, errorWorldNotFound, fmt.Sprintf("World %s does not exist.", id))
+		return
+	}
+
+	var body CountdownChange
+	if err := decodeBody(writer, request, &body); err != nil {
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, err.Error())
+		return
+	}
+
+	var state model.CountdownState
+	var err error
+	switch body.Action {
+	case countdownPause:
+		state, err = world.Game.PauseCountdown()
+	case countdownResume:
+		state, err = world.Game.ResumeCountdown()
+	case countdownExtend:
+		if body.Seconds <= 0 {
+			writeError(writer, http.StatusBadRequest, errorInvalidInput, "seconds must be a positive number to extend the countdown.")
+			return
+		}
+		state, err = world.Game.ExtendCountdown(time.Duration(body.Seconds) * time.Second)
+	case countdownReset:
+		state, err = world.Game.ResetCountdown()
+	default:
+		writeError(writer, http.StatusBadRequest, errorInvalidInput, fmt.Sprintf("action must be one of %s, %s, %s or %s.", countdownPause, countdownResume, countdownExtend, countdownReset))
+		return
+	}
+
+	switch {
+	case errors.Is(err, model.ErrNoCountdown):
+		writeError(writer, http.StatusConflict, errorNoCountdown, "This world was started without a time limit.")
+		return
+	case errors.Is(err, model.ErrGameOver):
+		writeError(writer, http.StatusConflict, errorGameOver, "The game in this world is over.")
+		return
+	}
+
+	world.watchCountdown()
+	writeJSON(writer, http.StatusOK, CountdownView{
+		WorldID:          world.ID,
+		LimitSeconds:     int(state.Limit / time.Second),
+		RemainingSeconds: *remainingSeconds(state),
+		Paused:           state.Paused,
+	})
+}
+
+// remainingSeconds rounds the time left up to the second, as players see it.
+func remainingSeconds(state model.CountdownState) *int {
+	seconds := int((state.Remaining + time.Second - 1) / time.Second)
+	return &seconds
+}
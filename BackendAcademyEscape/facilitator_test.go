@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func facilitatorRequest(handler http.Handler, method string, path string, body string, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestFacilitatorAPIRequiresToken(t *testing.T) {
+	//Arrange
+	configured := newServer()
+	configured.facilitatorToken = "secret"
+	unconfigured := newServer()
+
+	//Act
+	missing := facilitatorRequest(configured.handler(), http.MethodGet, "/api/v1/facilitator/sessions", "", "")
+	wrong := facilitatorRequest(configured.handler(), http.MethodGet, "/api/v1/facilitator/sessions", "", "guess")
+	disabled := facilitatorRequest(unconfigured.handler(), http.MethodGet, "/api/v1/facilitator/sessions", "", "")
+
+	//Assert
+	for name, recorder := range map[string]*httptest.ResponseRecorder{"missing": missing, "wrong": wrong, "disabled": disabled} {
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("%s token: expected status %d, got %d", name, http.StatusUnauthorized, recorder.Code)
+		}
+		if recorder.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s token: expected a WWW-Authenticate header", name)
+		}
+	}
+}
+
+func TestFacilitatorListsSessionProgress(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"player_name":"Ada"}`)
+	var created SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&created)
+	path := "/api/v1/sessions/" + created.ID + "/commands"
+	performRequest(handler, http.MethodPost, path, `{"command":"approach","args":["kettle"]}`)
+	performRequest(handler, http.MethodPost, path, `{"command":"take","args":["tea"]}`)
+	performRequest(handler, http.MethodPost, path, `{"command":"approach","args":["rosie"]}`)
+	performRequest(handler, http.MethodPost, path, `{"command":"use","args":["tea"]}`)
+
+	//Act
+	listed := facilitatorRequest(handler, http.MethodGet, "/api/v1/facilitator/sessions", "", "secret")
+
+	//Assert
+	var list SessionList
+	if err := json.NewDecoder(listed.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(list.Sessions))
+	}
+	summary := list.Sessions[0]
+	if summary.PlayerName != "Ada" || summary.Room != "break-room" {
+		t.Errorf("Expected Ada in break-room, got %s in %s", summary.PlayerName, summary.Room)
+	}
+	if len(summary.TriggeredEvents) != 1 || summary.TriggeredEvents[0] != "get-your-lanyard" {
+		t.Errorf("Expected only get-your-lanyard to be triggered, got %v", summary.TriggeredEvents)
+	}
+	if summary.RemainingPasswordAttempts != 10 {
+		t.Errorf("Expected 10 remaining password attempts, got %d", summary.RemainingPasswordAttempts)
+	}
+}
+
+func TestFacilitatorMessageAppearsInNextResponse(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	session := createTestSession(t, handler)
+
+	//Act
+	sent := facilitatorRequest(handler, http.MethodPost, "/api/v1/facilitator/sessions/"+session.ID+"/messages", `{"message":"Have you tried the kettle?"}`, "secret")
+	next := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", `{"command":"inventory"}`)
+	after := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", `{"command":"inventory"}`)
+
+	//Assert
+	if sent.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, sent.Code)
+	}
+	if !strings.Contains(next.Body.String(), "Message from the facilitator: Have you tried the kettle?") {
+		t.Errorf("Expected the facilitator message in the next response, got %s", next.Body.String())
+	}
+	if strings.Contains(after.Body.String(), "facilitator") {
+		t.Errorf("Expected the facilitator message to be shown only once, got %s", after.Body.String())
+	}
+}
+
+func TestFacilitatorTailsTranscript(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	session := createTestSession(t, handler)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", `{"command":"look"}`)
+	facilitatorRequest(handler, http.MethodPost, "/api/v1/facilitator/sessions/"+session.ID+"/messages", `{"message":"Nice start."}`, "secret")
+
+	//Act
+	recorder := facilitatorRequest(handler, http.MethodGet, "/api/v1/facilitator/sessions/"+session.ID+"/transcript?after=1", "", "secret")
+
+	//Assert
+	var transcript Transcript
+	if err := json.NewDecoder(recorder.Body).Decode(&transcript); err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript.Entries) != 2 {
+		t.Fatalf("Expected 2 entries after the first, got %d", len(transcript.Entries))
+	}
+	if entry := transcript.Entries[0]; entry.Seq != 2 || entry.Kind != transcriptCommand || entry.Command != "look" {
+		t.Errorf("Expected the look command as entry 2, got %+v", entry)
+	}
+	if entry := transcript.Entries[1]; entry.Kind != transcriptFacilitator || entry.Message != "Nice start." {
+		t.Errorf("Expected the facilitator message, got %+v", entry)
+	}
+}
@@ -1,3 +0,0 @@
-package global
-
-var GameOver = false
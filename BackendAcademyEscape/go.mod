@@ -2,4 +2,7 @@ module academy-adventure-game
 
 go 1.23
 
-require github.com/rs/cors v1.11.1
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/rs/cors v1.11.1
+)
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLanguageCommandSwitchesTheGameToFrench(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+
+	//Act
+	listed := run(game, "language")
+	switched := run(game, "language", "fr-CA")
+	look := run(game, "look")
+	missing := run(game, "take", "stapler")
+
+	//Assert
+	if listed.Message != "You are playing in en. Languages: en, fr.\n" {
+		t.Errorf("Expected the languages to be listed, got %q", listed.Message)
+	}
+	if switched.Message != "Vous jouez maintenant en fr.\n" {
+		t.Errorf("Expected to switch to French, got %q", switched.Message)
+	}
+	if !strings.HasPrefix(look.Message, "Vous êtes dans break-room\n\nUn salon chaleureux") {
+		t.Errorf("Expected the room in French, got %q", look.Message)
+	}
+	if missing.Message != "Vous ne pouvez pas prendre stapler\n" {
+		t.Errorf("Expected the engine message in French, got %q", missing.Message)
+	}
+}
+
+func TestMissingTranslationsFallBackToEnglish(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.SetCatalogue(model.Catalogue{
+		"pt":    {"take.missing": "Você não pode pegar %s\n"},
+		"pt-BR": {"move.blocked": "Não dá para ir por aí!\n"},
+	})
+	run(game, "language", "pt-BR")
+
+	//Act
+	regional := run(game, "move", "west")
+	language := run(game, "take", "stapler")
+	english := run(game, "approach", "stapler")
+
+	//Assert
+	if regional.Message != "Não dá para ir por aí!\n" {
+		t.Errorf("Expected the regional translation, got %q", regional.Message)
+	}
+	if language.Message != "Você não pode pegar stapler\n" {
+		t.Errorf("Expected to fall back to the language, got %q", language.Message)
+	}
+	if english.Message != "You can't approach stapler.\n" {
+		t.Errorf("Expected to fall back to English, got %q", english.Message)
+	}
+}
+
+func TestAcceptLanguagePicksTheSessionLocale(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/sessions", strings.NewReader(""))
+	request.Header.Set("Accept-Language", "de-DE, fr;q=0.8, en;q=0.5")
+	recorder := httptest.NewRecorder()
+
+	//Act
+	handler.ServeHTTP(recorder, request)
+	var created SessionCreated
+	json.NewDecoder(recorder.Body).Decode(&created)
+	response := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+created.ID+"/commands", `{"command": "take", "args": ["stapler"]}`)
+
+	//Assert
+	if !strings.Contains(response.Body.String(), "Vous ne pouvez pas prendre stapler") {
+		t.Errorf("Expected the session to be in French, got %q", response.Body.String())
+	}
+}
+
+func TestCatalogueMatchesAcceptLanguage(t *testing.T) {
+	//Arrange
+	catalogue := model.Catalogue{"fr": {}, "pt-BR": {}}
+
+	//Act
+	french := catalogue.Match("fr-CH, fr;q=0.9, en;q=0.8")
+	brazilian := catalogue.Match("en;q=0.2, pt-br;q=0.7")
+	unknown := catalogue.Match("de")
+
+	//Assert
+	if french != "fr" || brazilian != "pt-BR" || unknown != model.DefaultLocale {
+		t.Errorf("Expected fr, pt-BR and en, got %s, %s and %s", french, brazilian, unknown)
+	}
+}
+
+func TestWhatEverybodyIsToldComesInTheirOwnLanguage(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	game.AddBehaviour("rosie", &model.Patrol{Route: []string{"break-room", "coding-lab"}, Every: 1})
+	sam, _ := game.Join("Sam")
+	lou, _ := game.Join("Lou")
+	game.SetLocale(lou, "fr")
+
+	//Act
+	english := game.RunGameAs(sam, model.PlayerInput{Command: "look"})
+	french := game.RunGameAs(lou, model.PlayerInput{Command: "look"})
+
+	//Assert
+	if !strings.Contains(english.Message, "rosie leaves for the coding-lab.") {
+		t.Errorf("Expected Rosie to leave in English, got %q", english.Message)
+	}
+	if !strings.HasPrefix(french.Message, "rosie part vers coding-lab.\n\n") {
+		t.Errorf("Expected to hear Rosie leave in French, got %q", french.Message)
+	}
+}
+
+func TestChatIsHeardInTheListenersLanguage(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	sam, _ := game.Join("Sam")
+	lou, _ := game.Join("Lou")
+	game.SetLocale(lou, "fr")
+
+	//Act
+	game.RunGameAs(sam, model.PlayerInput{Command: "say", Args: []string{"hello"}})
+	game.RunGameAs(sam, model.PlayerInput{Command: "whisper", Args: []string{"Lou", "psst"}})
+	heard := game.RunGameAs(lou, model.PlayerInput{Command: "time"})
+
+	//Assert
+	if !strings.HasPrefix(heard.Message, "Sam dit : hello\n\nSam vous chuchote : psst\n\n") {
+		t.Errorf("Expected Sam's words to be introduced in French, got %q", heard.Message)
+	}
+}
+
+func TestReactionsAreTranslated(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addRosiesPatrol(game)
+	giveRosieTea(game)
+	run(game, "approach", "sofa")
+	waitFor(t, game, "rosie leaves for the coding-lab.")
+	run(game, "language", "fr")
+	run(game, "take", "abandoned-lanyard")
+
+	//Act
+	returned := run(game, "give", "abandoned-lanyard", "to", "sofa")
+
+	//Assert
+	if !strings.HasPrefix(returned.Message, "Vous reposez discrètement le badge") {
+		t.Errorf("Expected the sofa's reaction in French, got %q", returned.Message)
+	}
+}
+
+func TestHelpAndUsageAreTranslated(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "language", "fr")
+
+	//Act
+	commands := run(game, "commands")
+	usage := run(game, "unlock")
+	brief := run(game, "brief")
+
+	//Assert
+	if !strings.HasPrefix(commands.Message, "-exit -> quitte la partie\n") || !strings.Contains(commands.Message, "-say <texte> -> pour parler aux joueurs dans la pièce\n") {
+		t.Errorf("Expected the commands in French, got %q", commands.Message)
+	}
+	if usage.Message != "Précisez une direction à déverrouiller (par exemple, unlock south)." {
+		t.Errorf("Expected the usage in French, got %q", usage.Message)
+	}
+	if brief.Message != "Les pièces ne seront décrites que la première fois que vous les voyez.\n" {
+		t.Errorf("Expected the setting to be confirmed in French, got %q", brief.Message)
+	}
+}
+
+func TestWorldContentTranslatesOutsideDescriptions(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "language", "fr")
+	unlockAlansComputer(game)
+	run(game, "take", "first-plate", "from", "stack")
+
+	//Act
+	refused := run(game, "drop", "first-plate")
+
+	//Assert
+	if !strings.HasPrefix(refused.Message, "Vous ne pouvez pas laisser traîner ces assiettes !") {
+		t.Errorf("Expected the drop refusal in French, got %q", refused.Message)
+	}
+}
@@ -1,63 +1,20 @@
 package main
 
 import (
-	"academy-adventure-game/global"
-	"academy-adventure-game/model"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/rs/cors"
 )
 
-var game *model.Game
+// allowedOrigins are the web clients that may call the API from a browser.
+var allowedOrigins = []string{"http://localhost:5173"}
 
-	
 func rootHandler(writer http.ResponseWriter, request *http.Request) {
 	fmt.Fprintf(writer, "Hello, this is the Academy adventure game!")
 }
 
-func startGame(writer http.ResponseWriter, request *http.Request) {
-
-		var playerInput model.PlayerInput
-
-		err := json.NewDecoder(request.Body).Decode(&playerInput)
-
-		if err != nil {
-            fmt.Println("Error decoding request body:", err)
-            http.Error(writer, "Bad Request Body", http.StatusBadRequest)
-            return
-        }
-
-		response := game.RunGame(playerInput)
-
-		json.NewEncoder(writer).Encode(response)
-
-		if response.GameOver {
-			global.GameOver = true
-			return
-		}
-}
-
-func getAvailableActions(writer http.ResponseWriter, request *http.Request) {
-	
-	var command model.GameCommand
-
-	err := json.NewDecoder(request.Body).Decode(&command)
-
-	if err != nil {
-		fmt.Println("Error decoding request body:", err)
-		http.Error(writer, "Bad Request Body", http.StatusBadRequest)
-		return
-	}
-
-	response := game.GetAvailableActions(command.Command)
-
-	json.NewEncoder(writer).Encode(response)
-
-}
-
-
 func CorsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -67,36 +24,20 @@ func CorsMiddleware(next http.Handler) http.Handler {
 
 func main() {
 
-	// commands := []string{"start", "look"}
-
-	// game := &model.Game{}
-    // game.SetupGame()
-
-	// for _, command := range commands {
-
-	// 		gameResponse := game.RunGame(model.PlayerInput{Command: command, Args: []string{}})
-	
-	// 		fmt.Printf("response: %s", gameResponse.Message)
-	// 	}
-
-
-	// 	fmt.Printf("response: %s", gameResponse.Message)
-	router := http.NewServeMux()
-
-	router.HandleFunc("/", rootHandler)
-	router.HandleFunc("/GameResponse", startGame)
-	router.HandleFunc("/CommandOptions", getAvailableActions)
-
-	game = &model.Game{}
-	game.SetupGame()
-
 	c := cors.New(cors.Options{
-		AllowedOrigins: []string{"http://localhost:5173"},
+		AllowedOrigins: allowedOrigins,
 		AllowedMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Content-Type"},
+		AllowedHeaders: []string{"Content-Type", "Last-Event-ID", "Authorization"},
+		ExposedHeaders: []string{"Location"},
 	})
 
-	handler := c.Handler(router)
+	s := newServer()
+	s.facilitatorToken = os.Getenv("FACILITATOR_TOKEN")
+	if s.facilitatorToken == "" {
+		fmt.Println("FACILITATOR_TOKEN is not set, the facilitator API is disabled.")
+	}
+
+	handler := c.Handler(s.handler())
 
 	fmt.Println("Server listening on port 8080...")
 	err := http.ListenAndServe(":8080", handler)
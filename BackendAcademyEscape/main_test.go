@@ -56,10 +56,10 @@ func (mockDisplay *MockDisplay) Show(text string) string {
 
 func TestPlayerCanMoveToAvailableRoom(t *testing.T) {
 	//Arrange
-	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Room)}
-	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Room)}
-	room1.Exits["north"] = &room2
-	room2.Exits["south"] = &room1
+	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Exit)}
+	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Exit)}
+	room1.Exits["north"] = &model.Exit{To: &room2}
+	room2.Exits["south"] = &model.Exit{To: &room1}
 
 	player := model.Player{CurrentRoom: &room1} // mock display as a field of player - a possibility
 
@@ -83,10 +83,10 @@ func TestPlayerCanMoveToAvailableRoom(t *testing.T) {
 
 func TestPlayerCannotMoveToUnavailableRoom(t *testing.T) {
 	//Arrange
-	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Room)}
-	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Room)}
-	room1.Exits["north"] = &room2
-	room2.Exits["south"] = &room1
+	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Exit)}
+	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Exit)}
+	room1.Exits["north"] = &model.Exit{To: &room2}
+	room2.Exits["south"] = &model.Exit{To: &room1}
 
 	player := model.Player{CurrentRoom: &room1}
 
@@ -253,10 +253,10 @@ func TestCanDropItemFromInventoryIntoRoom(t *testing.T) {
 
 func TestCannotDropAbsentItem(t *testing.T) {
 	//Arrange
-	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Room), Items: make(map[string]*model.Item)}
-	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Room), Items: make(map[string]*model.Item)}
-	room1.Exits["north"] = &room2
-	room2.Exits["south"] = &room1
+	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Exit), Items: make(map[string]*model.Item)}
+	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Exit), Items: make(map[string]*model.Item)}
+	room1.Exits["north"] = &model.Exit{To: &room2}
+	room2.Exits["south"] = &model.Exit{To: &room1}
 
 	item := model.Item{Name: "Item", Description: "This is an item."}
 
@@ -364,17 +364,9 @@ func TestShowRoom(t *testing.T) {
 
 	// Assert
 	output := strings.Join(mockDisplay.Output, "")
-	expectedOutput := fmt.Sprintf(
-		"You are in %s\n\n%s\n\nYou can approach:\n- %s\n\nThe room contains:\n- %s: %s Weight: %d",
-		room.Name,
-		room.Description,
-		entity.Name,
-		item.Name,
-		item.Description,
-		item.Weight,
-	)
+	expectedOutput := "You are in Room 1\n\nThis is room 1.\n\nYou can approach:\n- Entity\n\nThe room contains:\n- Item: This is an item. Weight: 10\n"
 
-	if strings.TrimSpace(output) != strings.TrimSpace(expectedOutput) {
+	if output != expectedOutput {
 		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
 	}
 }
@@ -396,18 +388,9 @@ func TestShowRoomEngagedEntity(t *testing.T) {
 
 	// Assert
 	output := strings.Join(mockDisplay.Output, "")
+	expectedOutput := "You are in Room 1\n\nThis is room 1.\n\nYou can approach:\n- Entity (currently approached)\n\nThe room contains:\n- Item: This is an item. Weight: 10\n"
 
-	expectedOutput := fmt.Sprintf(
-		"You are in %s\n\n%s\n\nYou can approach:\n- %s (currently approached)\n\nThe room contains:\n- %s: %s Weight: %d",
-		room.Name,
-		room.Description,
-		entity.Name,
-		item.Name,
-		item.Description,
-		item.Weight,
-	)
-
-	if strings.TrimSpace(output) != strings.TrimSpace(expectedOutput) {
+	if output != expectedOutput {
 		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
 	}
 }
@@ -678,11 +661,11 @@ func TestShouldDisengageEntity(t *testing.T) {
 
 func TestPlayerMoveShouldDisengageEntity(t *testing.T) {
 	//Arrange
-	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Room), Entities: make(map[string]*model.Entity)}
-	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Room), Entities: make(map[string]*model.Entity)}
+	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Exit), Entities: make(map[string]*model.Entity)}
+	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Exit), Entities: make(map[string]*model.Entity)}
 
-	room1.Exits["north"] = &room2
-	room2.Exits["south"] = &room1
+	room1.Exits["north"] = &model.Exit{To: &room2}
+	room2.Exits["south"] = &model.Exit{To: &room1}
 
 	entity := model.Entity{Name: "Entity", Description: "This is an entity"}
 	room1.Entities[entity.Name] = &entity
@@ -702,7 +685,7 @@ func TestPlayerMoveShouldDisengageEntity(t *testing.T) {
 
 func TestNewEngagementShouldCancelFormer(t *testing.T) {
 	//Arrange
-	room := model.Room{Name: "Room", Description: "This is a room.", Exits: make(map[string]*model.Room), Entities: make(map[string]*model.Entity)}
+	room := model.Room{Name: "Room", Description: "This is a room.", Exits: make(map[string]*model.Exit), Entities: make(map[string]*model.Entity)}
 
 	entity1 := model.Entity{Name: "Entity", Description: "This is an entity"}
 	entity2 := model.Entity{Name: "Entity 2", Description: "This is an entity"}
@@ -725,11 +708,11 @@ func TestNewEngagementShouldCancelFormer(t *testing.T) {
 
 func TestShowMap(t *testing.T) {
 	//Arrange
-	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Room), Entities: make(map[string]*model.Entity)}
-	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Room), Entities: make(map[string]*model.Entity)}
+	room1 := model.Room{Name: "Room 1", Description: "This is room 1.", Exits: make(map[string]*model.Exit), Entities: make(map[string]*model.Entity)}
+	room2 := model.Room{Name: "Room 2", Description: "This is room 2.", Exits: make(map[string]*model.Exit), Entities: make(map[string]*model.Entity)}
 
-	room1.Exits["north"] = &room2
-	room2.Exits["south"] = &room1
+	room1.Exits["north"] = &model.Exit{To: &room2}
+	room2.Exits["south"] = &model.Exit{To: &room1}
 
 	player := model.Player{CurrentRoom: &room1}
 
@@ -741,7 +724,7 @@ func TestShowMap(t *testing.T) {
 	// Assert
 	output := strings.Join(mockDisplay.Output, "")
 
-	expectedOutput := fmt.Sprintf("north: %s\n", player.CurrentRoom.Exits["north"].Name)
+	expectedOutput := fmt.Sprintf("north: %s\n", player.CurrentRoom.Exits["north"].To.Name)
 
 	if output != expectedOutput {
 		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
@@ -885,3 +868,172 @@ func TestShowCommands(t *testing.T) {
 		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
 	}
 }
+
+func TestGiveItemToEntityTriggersInteraction(t *testing.T) {
+	//Arrange
+	setUpValidInteractions()
+	room := model.Room{Items: make(map[string]*model.Item), Entities: make(map[string]*model.Entity)}
+	key := model.Item{Name: "key", Weight: 1}
+	door := model.Entity{Name: "door"}
+	room.Entities[door.Name] = &door
+	player := model.Player{CurrentRoom: &room, Inventory: make(map[string]*model.Item), AvailableWeight: 29}
+	player.Inventory[key.Name] = &key
+	mockDisplay := &MockDisplay{}
+
+	//Act
+	output := player.GiveToEntity("key", &door, mockDisplay)
+
+	//Assert
+	if !model.ValidInteractions[0].Event.Triggered {
+		t.Errorf("Expected event to be true for triggered, got false")
+	}
+	if output != model.ValidInteractions[0].Event.Outcome {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", model.ValidInteractions[0].Event.Outcome, output)
+	}
+	if _, ok := door.Inventory["key"]; !ok {
+		t.Errorf("Expected the door to hold the key")
+	}
+	if player.AvailableWeight != 30 {
+		t.Errorf("Expected available weight 30, got %d", player.AvailableWeight)
+	}
+}
+
+func TestGiveItemToEntityThatHandsSomethingBack(t *testing.T) {
+	//Arrange
+	setUpValidInteractions()
+	room := model.Room{Items: make(map[string]*model.Item), Entities: make(map[string]*model.Entity)}
+	coin := model.Item{Name: "coin", Weight: 1}
+	ticket := model.Item{Name: "ticket", Weight: 2}
+	machine := model.Entity{
+		Name:      "machine",
+		Inventory: map[string]*model.Item{"ticket": &ticket},
+		Reactions: []*model.Reaction{{ItemName: "coin", Accept: true, Response: "The machine prints a ticket.\n", HandsBack: "ticket"}},
+	}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{"coin": &coin}, AvailableWeight: 10}
+	mockDisplay := &MockDisplay{}
+
+	//Act
+	player.GiveToEntity("coin", &machine, mockDisplay)
+
+	//Assert
+	output := strings.Join(mockDisplay.Output, "")
+	if output != "The machine prints a ticket.\n" {
+		t.Errorf("Expected output:\nThe machine prints a ticket.\nGot:\n%s", output)
+	}
+	if _, ok := player.Inventory["ticket"]; !ok {
+		t.Errorf("Expected the ticket to be handed back")
+	}
+	if _, ok := machine.Inventory["coin"]; !ok {
+		t.Errorf("Expected the machine to keep the coin")
+	}
+	if player.AvailableWeight != 9 {
+		t.Errorf("Expected available weight 9, got %d", player.AvailableWeight)
+	}
+}
+
+func TestGiveItemToEntityThatRejectsIt(t *testing.T) {
+	//Arrange
+	setUpValidInteractions()
+	room := model.Room{Items: make(map[string]*model.Item), Entities: make(map[string]*model.Entity)}
+	water := model.Item{Name: "water", Weight: 1}
+	cat := model.Entity{Name: "cat"}
+	player := model.Player{CurrentRoom: &room, Inventory: map[string]*model.Item{"water": &water}}
+	mockDisplay := &MockDisplay{}
+
+	//Act
+	player.GiveToEntity("water", &cat, mockDisplay)
+
+	//Assert
+	output := strings.Join(mockDisplay.Output, "")
+	if output != "cat doesn't want water.\n" {
+		t.Errorf("Expected output:\ncat doesn't want water.\nGot:\n%s", output)
+	}
+	if _, ok := player.Inventory["water"]; !ok {
+		t.Errorf("Expected the rejected item to stay in the inventory")
+	}
+}
+
+func TestGiveItemToPlayerRespectsTheirWeightLimit(t *testing.T) {
+	//Arrange
+	room := model.Room{Items: make(map[string]*model.Item), Entities: make(map[string]*model.Entity)}
+	anvil := model.Item{Name: "anvil", Weight: 8}
+	giver := model.Player{Name: "Ada", CurrentRoom: &room, Inventory: map[string]*model.Item{"anvil": &anvil}, CarriedWeight: 8}
+	recipient := model.Player{Name: "Grace", CurrentRoom: &room, Inventory: make(map[string]*model.Item), AvailableWeight: 5}
+	mockDisplay := &MockDisplay{}
+
+	//Act
+	giver.GiveToPlayer("anvil", &recipient, mockDisplay)
+	recipient.AvailableWeight = 10
+	giver.GiveToPlayer("anvil", &recipient, mockDisplay)
+
+	//Assert
+	output := strings.Join(mockDisplay.Output, "")
+	expectedOutput := "Grace can't carry anvil.\nYou gave anvil to Grace.\n"
+	if output != expectedOutput {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+	}
+	if _, ok := recipient.Inventory["anvil"]; !ok {
+		t.Errorf("Expected Grace to hold the anvil")
+	}
+	if recipient.AvailableWeight != 2 || giver.CarriedWeight != 0 {
+		t.Errorf("Expected weights to move with the anvil, got %d available and %d carried", recipient.AvailableWeight, giver.CarriedWeight)
+	}
+}
+
+func TestShowRoomListsThingsInAlphabeticalOrder(t *testing.T) {
+	//Arrange
+	room := model.Room{Name: "Room 1", Description: "This is room 1.", Items: make(map[string]*model.Item), Entities: make(map[string]*model.Entity)}
+	for _, name := range []string{"zebra", "alpaca", "moose", "badger"} {
+		room.Entities[name] = &model.Entity{Name: name}
+		room.Items[name+"-toy"] = &model.Item{Name: name + "-toy", Description: "A toy.", Weight: 1}
+	}
+	player := model.Player{CurrentRoom: &room}
+
+	for i := 0; i < 10; i++ {
+		mockDisplay := &MockDisplay{}
+
+		//Act
+		player.ShowRoom(mockDisplay)
+
+		//Assert
+		output := strings.Join(mockDisplay.Output, "")
+		expectedOutput := "You are in Room 1\n\nThis is room 1.\n\nYou can approach:\n- alpaca\n- badger\n- moose\n- zebra\n\nThe room contains:\n- alpaca-toy: A toy. Weight: 1\n\n- badger-toy: A toy. Weight: 1\n\n- moose-toy: A toy. Weight: 1\n\n- zebra-toy: A toy. Weight: 1\n"
+		if output != expectedOutput {
+			t.Fatalf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+		}
+	}
+}
+
+func TestMoveActionsAreDirections(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	id, _ := game.Join("Ada")
+	game.AddExit("break-room", "west", "terminal-room", &model.Exit{})
+
+	//Act
+	actions, _ := game.GetAvailableActionsFor(id, "move")
+
+	//Assert
+	if strings.Join(actions.Actions, ",") != "south,west" {
+		t.Errorf("Expected the directions south and west, got %v", actions.Actions)
+	}
+}
+
+func TestAvailableActionsAreInAlphabeticalOrder(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	id, _ := game.Join("Ada")
+
+	for i := 0; i < 10; i++ {
+		//Act
+		actions, _ := game.GetAvailableActionsFor(id, "approach")
+
+		//Assert
+		expected := []string{"cat", "kettle", "rosie", "sofa"}
+		if strings.Join(actions.Actions, ",") != strings.Join(expected, ",") {
+			t.Fatalf("Expected %v, got %v", expected, actions.Actions)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessibleDisplay shows responses as linear text for screen readers:
+// lists are read out as one sentence with a count instead of bullets, code
+// listings are announced, and blank lines and decorative dashes are left
+// out. Rooms also say which exits there are.
+type AccessibleDisplay struct {
+	// reader is the player it reads to, whose language it speaks. It is
+	// English without one.
+	reader *Player
+}
+
+// text is a message in the reader's language.
+func (d AccessibleDisplay) text(id string, english string, args ...any) string {
+	if d.reader == nil {
+		return fmt.Sprintf(english, args...)
+	}
+	return d.reader.text(id, english, args...)
+}
+
+func (d AccessibleDisplay) Show(text string) string {
+	var out strings.Builder
+	nodes := groupLists(ParseMarkup(text))
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		switch {
+		case node.Type == HeadingNode && i+2 < len(nodes) && isBlank(nodes[i+1]) && nodes[i+2].Type == ListNode:
+			out.WriteString("\n" + d.countedList(listHeading(node), nodes[i+2]) + "\n")
+			i += 2
+		case node.Type == HeadingNode && i+1 < len(nodes) && nodes[i+1].Type == ListNode:
+			out.WriteString("\n" + d.countedList(listHeading(node), nodes[i+1]) + "\n")
+			i++
+		case node.Type == ListNode:
+			out.WriteString("\n" + d.countedList("", node) + "\n")
+		case node.Type == CodeNode:
+			lines := strings.Split(strings.TrimRight(plainOf(node.Children), "\n"), "\n")
+			out.WriteString(d.text("accessible.code", "\nCode listing, %d lines:\n", len(lines)))
+			for _, line := range lines {
+				out.WriteString(strings.TrimSpace(line) + "\n")
+			}
+			out.WriteString(d.text("accessible.code.end", "End of code listing.\n"))
+		default:
+			out.WriteString(plainOf([]Node{node}))
+		}
+	}
+	return linear(out.String())
+}
+
+func isBlank(node Node) bool {
+	return node.Type == TextNode && strings.TrimSpace(node.Text) == ""
+}
+
+// listHeading is a heading without its colon, and the space French puts
+// before one.
+func listHeading(heading Node) string {
+	return strings.TrimSpace(strings.TrimSuffix(plainOf(heading.Children), ":"))
+}
+
+// countedList reads a list out as "You can approach 2 things: rosie and
+// cat."
+func (d AccessibleDisplay) countedList(heading string, list Node) string {
+	var entries []string
+	for _, entry := range list.Children {
+		entries = append(entries, strings.TrimSuffix(strings.TrimSpace(plainOf(entry.Children)), "."))
+	}
+	things := d.text("accessible.things", "things")
+	if len(entries) == 1 {
+		things = d.text("accessible.thing", "thing")
+	}
+	sentence := d.text("accessible.list", "%d %s: %s.", len(entries), things, joinWithAnd(entries, d.text("accessible.and", "and")))
+	if heading == "" {
+		return sentence
+	}
+	return heading + " " + sentence
+}
+
+func joinWithAnd(words []string, and string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + and + " " + words[len(words)-1]
+}
+
+// linear drops blank lines and dashes used as decoration.
+func linear(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, " — ", ", "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// exitsSentence says which ways a player can go, for AccessibleDisplay.
+func (p *Player) exitsSentence() string {
+	var exits []string
+	for _, direction := range sortedNames(p.CurrentRoom.Exits) {
+		exit := p.CurrentRoom.Exits[direction]
+		if exit.Hidden {
+			continue
+		}
+		if !exit.opensFor(p) {
+			direction = p.text("ui.room.exits.locked", "%s (locked)", direction)
+		}
+		exits = append(exits, direction)
+	}
+	if len(exits) == 0 {
+		return p.text("ui.room.exits.none", "There are no exits.\n")
+	}
+	return p.text("ui.room.exits", "Exits are: %s.\n", joinWithAnd(exits, p.text("accessible.and", "and")))
+}
+
+// accessible reports whether the player reads the game with a screen
+// reader.
+func (p *Player) accessible() bool {
+	_, ok := p.display().(AccessibleDisplay)
+	return ok
+}
+
+// Room descriptions are shown on every look but not after moving, unless a
+// player asks for verbose descriptions, shown after every move as well, or
+// brief ones, shown only the first time they see a room.
+const (
+	Verbose = "verbose"
+	Brief   = "brief"
+)
+
+// describesRoom reports whether the player should be given the description
+// of the room they are in, and remembers that they have seen it.
+func (p *Player) describesRoom() bool {
+	seen := p.visited[p.CurrentRoom]
+	p.visit(p.CurrentRoom)
+	return p.Descriptions != Brief || !seen
+}
+
+// visit remembers that the player has been in a room, for brief
+// descriptions and the map.
+func (p *Player) visit(room *Room) {
+	if p.visited == nil {
+		p.visited = make(map[*Room]bool)
+	}
+	p.visited[room] = true
+}
+
+// arrival is what the player is told on walking into a room.
+func (p *Player) arrival() string {
+	if p.Descriptions == Verbose || (p.Descriptions == Brief && !p.visited[p.CurrentRoom]) {
+		return p.ShowRoom(ConsoleDisplay{})
+	}
+	return p.text("move.arrived", "You are in %s\n", p.CurrentRoom.Name)
+}
@@ -0,0 +1,247 @@
+package model
+
+import "errors"
+
+var ErrUnknownEntity = errors.New("no such entity in this world")
+
+// Behaviour is a script an NPC follows. Act runs on every tick of the game
+// clock, unless the NPC is hidden.
+type Behaviour interface {
+	Act(npc *NPC, game *Game, t *turn)
+}
+
+// NPC is an entity that can act and move between rooms by itself.
+type NPC struct {
+	Entity     *Entity
+	Room       *Room
+	Behaviours []Behaviour
+}
+
+// turn is one tick of the clock, collecting what players are told.
+type turn struct {
+	actor    *Player
+	response *GameResponse
+}
+
+// tell shows a message to the given players, each in their own language:
+// the acting player in this turn's response, everybody else at the top of
+// their next one.
+func (t *turn) tell(game *Game, to []*Player, id string, english string, args ...any) {
+	for _, player := range to {
+		message := player.text(id, english, args...)
+		if player == t.actor {
+			t.response.Message += "\n" + message + "\n"
+		} else {
+			player.hear(message + "\n\n")
+		}
+	}
+}
+
+func (game *Game) activePlayers() []*Player {
+	var players []*Player
+	for _, player := range game.players {
+		if !player.exited {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func (game *Game) playersIn(room *Room) []*Player {
+	var players []*Player
+	for _, player := range game.players {
+		if !player.exited && player.CurrentRoom == room {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// moveNPC walks an NPC into another room, telling the players it leaves
+// behind and the players it joins.
+func (game *Game) moveNPC(npc *NPC, to *Room, t *turn) {
+	if to == nil || to == npc.Room {
+		return
+	}
+	from := npc.Room
+	for _, player := range game.playersIn(from) {
+		if player.CurrentEntity == npc.Entity {
+			player.CurrentEntity = nil
+		}
+	}
+	delete(from.Entities, npc.Entity.Name)
+	to.Entities[npc.Entity.Name] = npc.Entity
+	npc.Room = to
+
+	t.tell(game, game.playersIn(from), "ui.npc.leaves", "%s leaves for the %s.", npc.Entity.Name, to.Name)
+	t.tell(game, game.playersIn(to), "ui.npc.arrives", "%s walks in.", npc.Entity.Name)
+	game.emit(GameEvent{Type: NPCMoved, Target: npc.Entity.Name, Room: to.Name})
+}
+
+// runNPCs lets every visible NPC act, in the order they were added.
+func (game *Game) runNPCs(t *turn) {
+	for _, npc := range game.npcs {
+		if npc.Entity.Hidden {
+			continue
+		}
+		for _, behaviour := range npc.Behaviours {
+			behaviour.Act(npc, game, t)
+		}
+	}
+}
+
+func (game *Game) rooms() []*Room {
+	return []*Room{game.staffRoom, game.codingLab, game.terminalRoom}
+}
+
+func (game *Game) roomNamed(name string) *Room {
+	for _, room := range game.rooms() {
+		if room.Name == name {
+			return room
+		}
+	}
+	return nil
+}
+
+// findEntity looks for an entity in every room, since NPCs move.
+func (game *Game) findEntity(name string) *Entity {
+	for _, room := range game.rooms() {
+		if entity, ok := room.Entities[name]; ok {
+			return entity
+		}
+	}
+	return nil
+}
+
+// AddBehaviour gives the entity with the given name a routine to follow,
+// making it an NPC if it isn't one already.
+func (game *Game) AddBehaviour(entity string, behaviour Behaviour) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	for _, npc := range game.npcs {
+		if npc.Entity.Name == entity {
+			npc.Behaviours = append(npc.Behaviours, behaviour)
+			return nil
+		}
+	}
+	for _, room := range game.rooms() {
+		if found, ok := room.Entities[entity]; ok {
+			game.npcs = append(game.npcs, &NPC{Entity: found, Room: room, Behaviours: []Behaviour{behaviour}})
+			return nil
+		}
+	}
+	return ErrUnknownEntity
+}
+
+// Patrol walks the NPC along Route, a list of room names, moving on every
+// Every turns and starting again from the top at the end.
+type Patrol struct {
+	Route []string
+	Every int
+	turns int
+	next  int
+}
+
+func (p *Patrol) Act(npc *NPC, game *Game, t *turn) {
+	p.turns++
+	if p.turns < p.Every {
+		return
+	}
+	p.turns = 0
+	p.next = (p.next + 1) % len(p.Route)
+	game.moveNPC(npc, game.roomNamed(p.Route[p.next]), t)
+}
+
+// Follow makes the NPC trail after the last player who approached it.
+type Follow struct {
+	Message string
+	leader  *Player
+}
+
+func (f *Follow) Act(npc *NPC, game *Game, t *turn) {
+	for _, player := range game.players {
+		if player.CurrentEntity == npc.Entity {
+			f.leader = player
+		}
+	}
+	if f.leader == nil || f.leader.exited || f.leader.CurrentRoom == npc.Room {
+		return
+	}
+	game.moveNPC(npc, f.leader.CurrentRoom, t)
+	if f.Message != "" {
+		t.tell(game, []*Player{f.leader}, "npc."+npc.Entity.Name+".follow", f.Message)
+	}
+}
+
+// Greet has the NPC say Message the first time each player enters its room.
+type Greet struct {
+	Message string
+	greeted map[*Player]bool
+}
+
+func (g *Greet) Act(npc *NPC, game *Game, t *turn) {
+	if g.greeted == nil {
+		g.greeted = make(map[*Player]bool)
+	}
+	for _, player := range game.playersIn(npc.Room) {
+		if !g.greeted[player] {
+			g.greeted[player] = true
+			t.tell(game, []*Player{player}, "npc."+npc.Entity.Name+".greet", g.Message)
+		}
+	}
+}
+
+// After holds Then back until the named puzzle event has been triggered.
+type After struct {
+	Event string
+	Then  Behaviour
+}
+
+func (a *After) Act(npc *NPC, game *Game, t *turn) {
+	if game.eventTriggered(a.Event) {
+		a.Then.Act(npc, game, t)
+	}
+}
+
+// Guard catches any player in the NPC's room who is carrying Item, which
+// loses the game for everybody.
+type Guard struct {
+	Item    string
+	Message string
+}
+
+func (g *Guard) Act(npc *NPC, game *Game, t *turn) {
+	for _, player := range game.playersIn(npc.Room) {
+		if _, ok := player.Inventory[g.Item]; ok && !player.caught {
+			player.caught = true
+			t.tell(game, game.playersIn(npc.Room), "npc."+npc.Entity.Name+".guard", g.Message)
+		}
+	}
+}
+
+func (game *Game) eventTriggered(description string) bool {
+	for _, interaction := range game.validInteractions {
+		if interaction.Event.Description == description {
+			return interaction.Event.Triggered
+		}
+	}
+	for _, event := range []*Event{game.unlockComputer, game.dishwasherChallengeWon} {
+		if event.Description == description {
+			return event.Triggered
+		}
+	}
+	return false
+}
+
+// setUpNPCs has Rosie guard the lanyard on the sofa.
+func (game *Game) setUpNPCs() {
+	game.npcs = []*NPC{
+		{
+			Entity: game.staffRoom.Entities["rosie"],
+			Room:   game.staffRoom,
+			Behaviours: []Behaviour{
+				&Guard{Item: "abandoned-lanyard", Message: "Rosie caught you in the act of swiping a lanyard from a fellow student.\nYou have made Rosie grumpy and you've lost the game."},
+			},
+		},
+	}
+}
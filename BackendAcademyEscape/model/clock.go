@@ -0,0 +1,183 @@
+package model
+
+import "fmt"
+
+// Clock is the time in the game world. It moves on by a command's cost in
+// minutes every time any player runs one that takes time.
+type Clock struct {
+	Turn    int
+	Minutes int
+}
+
+// String shows the time of day as HH:MM.
+func (clock Clock) String() string {
+	return formatTimeOfDay(clock.Minutes)
+}
+
+func formatTimeOfDay(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60%24, minutes%60)
+}
+
+const (
+	dayStartsAt        = 9 * 60
+	lockdownAt         = 16 * 60
+	defaultCommandCost = 1
+)
+
+// defaultCommandCosts are the minutes each command takes. Commands not
+// listed, including passwords and terminal input, take defaultCommandCost.
+var defaultCommandCosts = map[string]int{
+	"start":         0,
+	"time":          0,
+	"commands":      0,
+	"exit":          0,
+	"look":          1,
+	"map":           1,
+	"approach":      2,
+	"take":          2,
+	"drop":          1,
+	"give":          2,
+	"use":           3,
+	"move":          5,
+	"say":           1,
+	"shout":         1,
+	"whisper":       1,
+	"unlock":        2,
+	"lock":          2,
+	"put":           2,
+	"combine":       2,
+	"language":      0,
+	"verbose":       0,
+	"brief":         0,
+	"accessibility": 0,
+}
+
+// ScheduledEvent happens once, on the first turn that reaches AtTurn or the
+// first time the clock reaches AtTime (minutes since midnight), whichever is
+// set. Run changes the world and returns what every player is told, or ""
+// for nothing.
+type ScheduledEvent struct {
+	Name   string
+	AtTurn int
+	AtTime int
+	Run    func(game *Game) string
+	done   bool
+}
+
+func (event *ScheduledEvent) isDue(clock Clock) bool {
+	if event.AtTurn > 0 && clock.Turn >= event.AtTurn {
+		return true
+	}
+	return event.AtTime > 0 && clock.Minutes >= event.AtTime
+}
+
+// SetCommandCost changes how many minutes a command takes in this world.
+func (game *Game) SetCommandCost(command string, minutes int) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.commandCosts[command] = minutes
+}
+
+// Schedule adds an event to the world.
+func (game *Game) Schedule(event *ScheduledEvent) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.scheduled = append(game.scheduled, event)
+}
+
+// scheduleIn adds an event that happens turns from now.
+func (game *Game) scheduleIn(turns int, event *ScheduledEvent) {
+	event.AtTurn = game.clock.Turn + turns
+	game.scheduled = append(game.scheduled, event)
+}
+
+// Clock returns the current time in the world.
+func (game *Game) Clock() Clock {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	return game.clock
+}
+
+// tick moves the clock on by the cost of command, lets the NPCs act and
+// runs the events that have become due. The acting player is told what
+// happened in their response, everybody else at the top of their next one.
+func (game *Game) tick(player *Player, command string, response *GameResponse) {
+	cost, ok := game.commandCosts[command]
+	if !ok {
+		cost = defaultCommandCost
+	}
+	game.clock.Turn++
+	game.clock.Minutes += cost
+
+	t := &turn{actor: player, response: response}
+	game.runNPCs(t)
+	game.ageItems()
+	game.revealExits(t)
+
+	for _, event := range game.scheduled {
+		if game.gameOver || event.done || !event.isDue(game.clock) {
+			continue
+		}
+		event.done = true
+		message := event.Run(game)
+		game.emit(GameEvent{Type: TimedEvent, Event: event.Name, Message: message})
+		if message != "" {
+			t.tell(game, game.activePlayers(), "scheduled."+event.Name, message)
+		}
+	}
+
+	if game.anyPlayerLost() {
+		game.gameOver = true
+	}
+	if game.gameOver {
+		response.GameOver = true
+	}
+}
+
+// takesTime reports whether command moves the clock on. Commands that cost
+// nothing, like time, don't, and neither does anything the game doesn't
+// understand, unless the player is typing a password or at the terminal.
+func (game *Game) takesTime(player *Player, command string) bool {
+	if cost, ok := game.commandCosts[command]; ok {
+		return cost > 0
+	}
+	_, known := Commands[command]
+	return known || player.isAttemptingPassword || player.isAttemptingTerminal || command == game.computerPassword
+}
+
+// scheduleWorldEvents sets up the events every world starts with.
+func (game *Game) scheduleWorldEvents() {
+	game.scheduled = append(game.scheduled,
+		&ScheduledEvent{
+			Name:   "rosie-gets-impatient",
+			AtTurn: 30,
+			Run: func(game *Game) string {
+				if game.validInteractions[0].Event.Triggered {
+					return ""
+				}
+				return "Rosie sighs loudly from the break room: \"Is anybody making that tea or what?\""
+			},
+		},
+		&ScheduledEvent{
+			Name:   "lockdown",
+			AtTime: lockdownAt,
+			Run: func(game *Game) string {
+				game.gameOver = true
+				return fmt.Sprintf("It's %s. The shutters come down and the building locks for the night, with you still inside. Thank you for playing!", formatTimeOfDay(lockdownAt))
+			},
+		},
+	)
+}
+
+// teaCools is scheduled when the tea is brewed.
+func (game *Game) teaCools() *ScheduledEvent {
+	return &ScheduledEvent{
+		Name: "tea-cools",
+		Run: func(game *Game) string {
+			if game.validInteractions[0].Event.Triggered {
+				return ""
+			}
+			return "The tea is going cold."
+		},
+	}
+}
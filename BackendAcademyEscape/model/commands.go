@@ -1,122 +1,397 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Command interface {
-	Execute(input PlayerInput, game *Game) string
+	Execute(input PlayerInput, game *Game, player *Player) string
 }
 
 type LookCommand struct{}
 
-func (l LookCommand) Execute(input PlayerInput, game *Game) string {
-	return game.player.ShowRoom(ConsoleDisplay{})
+func (l LookCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+	display := ConsoleDisplay{}
+	room := player.ShowRoom(display)
+	others := game.otherPlayers(player, true)
+	if len(others) == 0 {
+		return room
+	}
+	room += show(display, player.text("look.others", "\n{h}Also here:{/h}\n"))
+	for _, other := range others {
+		room += show(display, fmt.Sprintf("{li}{player}%s{/player}{/li}\n", escapeMarkup(other.Name)))
+	}
+	return room
 }
 
 type ExitCommand struct{}
 
-func (e ExitCommand) Execute(input PlayerInput, game *Game) string { return "" }
+func (e ExitCommand) Execute(input PlayerInput, game *Game, player *Player) string { return "" }
 
 type CommandsCommand struct{}
 
 func ShowCommands(d Display) string {
-	return d.Show("-exit -> quits the game\n\n-commands -> shows the commands\n\n-look -> shows the content of the room.\n\n-approach <entity> -> to approach an entity\n\n-leave -> to leave an entity\n\n-inventory -> shows items in the inventory\n\n-take <item> -> to take an item into your inventory\n\n-drop <item> -> to drop an item from your inventory and move it to the current room\n\n-use <item> -> to make use of a certain item when you approach an entity\n\n-move <direction> -> to move to a different room\n\n-map -> shows the directions you can take\n")
+	return show(d, "-exit -> quits the game\n\n-commands -> shows the commands\n\n-look -> shows the content of the room.\n\n-approach <entity> -> to approach an entity\n\n-leave -> to leave an entity\n\n-inventory -> shows items in the inventory\n\n-take <item> -> to take an item into your inventory\n\n-drop <item> -> to drop an item from your inventory and move it to the current room\n\n-use <item> -> to make use of a certain item when you approach an entity\n\n-move <direction> -> to move to a different room\n\n-map -> shows the directions you can take\n")
+}
+
+func ShowMoreCommands(d Display) string {
+	return show(d, "\n-give <item> to <target> -> to hand an item to another player, or to someone in the room\n\n-time -> shows the time, and how long until the building locks down\n\n-unlock <direction> [password] -> to unlock the way out in that direction, if you have what it takes\n\n-lock <direction> -> to lock it again\n\n-put <item> in <container> -> to put an item into a container, like a drawer or a bag\n\n-take <item> from <container> -> to take an item out of a container\n\n-open <container>, close <container> -> to open or close a container\n\n-examine <thing> -> to take a closer look at an item, someone, a way out or part of the room\n\n-combine <item> with <item> -> to make something new out of two or more items, also use <item> on <item>\n\n-equip <item>, unequip <item> -> to wear an item, like your lanyard, or take it off\n\n-language [locale] -> to list the languages or play in another one, like language fr\n\n-verbose, brief -> to describe rooms every time you look or move, or only the first time you see them\n\n-accessibility [on|off] -> to switch to text that reads well with a screen reader\n")
+}
+
+func ShowChatCommands(d Display) string {
+	return show(d, "\n-say <text> -> to speak to the players in the room\n\n-shout <text> -> to speak to the players in every room\n\n-whisper <player> <text> -> to speak to one player in the room\n")
 }
 
-func (c CommandsCommand) Execute(input PlayerInput, game *Game) string {
+func (c CommandsCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
-	return ShowCommands(ConsoleDisplay{})
+	commands := player.text("commands", ShowCommands(ConsoleDisplay{})) + player.text("commands.more", ShowMoreCommands(ConsoleDisplay{}))
+	if game.chatDisabled {
+		return commands
+	}
+	return commands + player.text("commands.chat", ShowChatCommands(ConsoleDisplay{}))
 }
 
 type TakeCommand struct{}
 
-func (t TakeCommand) Execute(input PlayerInput, game *Game) string {
+func (t TakeCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	switch {
+	case len(input.Args) == 3 && input.Args[1] == "from":
+		return player.TakeFrom(input.Args[0], input.Args[2], ConsoleDisplay{})
+	case len(input.Args) == 2:
+		return player.TakeFrom(input.Args[0], input.Args[1], ConsoleDisplay{})
+	case len(input.Args) > 0:
+		return player.Take(input.Args[0], ConsoleDisplay{})
+	default:
+		return player.text("take.usage", "Specify an item to take.")
+	}
+}
+
+type CombineCommand struct{}
+
+func (c CombineCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	var names []string
+	for _, arg := range input.Args {
+		if arg != "with" && arg != "and" {
+			names = append(names, arg)
+		}
+	}
+	if len(names) < 2 {
+		return player.text("combine.usage", "Specify the items to combine (e.g., combine tea with milk).")
+	}
+	return game.combine(player, names)
+}
+
+type EquipCommand struct{}
+
+func (e EquipCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) > 0 {
+		return player.Equip(input.Args[0], ConsoleDisplay{})
+	} else {
+		return player.text("equip.usage", "Specify an item to equip.")
+	}
+}
+
+type UnequipCommand struct{}
+
+func (u UnequipCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) > 0 {
+		return player.Unequip(input.Args[0], ConsoleDisplay{})
+	} else {
+		return player.text("unequip.usage", "Specify an item to unequip.")
+	}
+}
+
+type LanguageCommand struct{}
+
+func (l LanguageCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) == 0 {
+		return player.text("language.list", "You are playing in %s. Languages: %s.\n", player.locale(), strings.Join(game.catalogue.Locales(), ", "))
+	}
+	locale := game.catalogue.supported(input.Args[0])
+	if locale == "" {
+		return player.text("language.unknown", "There is no %s translation. Languages: %s.\n", escapeMarkup(input.Args[0]), strings.Join(game.catalogue.Locales(), ", "))
+	}
+	player.Locale = locale
+	return player.text("language.done", "You are now playing in %s.\n", locale)
+}
+
+type VerboseCommand struct{}
+
+func (v VerboseCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	player.Descriptions = Verbose
+	return player.text("verbose", "Rooms will be described every time you look or move.\n")
+}
+
+type BriefCommand struct{}
+
+func (b BriefCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	player.Descriptions = Brief
+	return player.text("brief", "Rooms will only be described the first time you see them.\n")
+}
+
+type AccessibilityCommand struct{}
+
+func (a AccessibilityCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	on := !player.accessible()
+	if len(input.Args) > 0 {
+		on = input.Args[0] != "off"
+	}
+	if on {
+		player.Display = AccessibleDisplay{}
+		return player.text("accessibility.on", "Accessibility mode is on. Lists are read out in full and rooms say which exits there are.\n")
+	}
+	player.Display = PlainDisplay{}
+	return player.text("accessibility.off", "Accessibility mode is off.\n")
+}
+
+type ExamineCommand struct{}
+
+func (e ExamineCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) > 0 {
+		return game.examine(player, input.Args[0])
+	} else {
+		return player.text("ui.examine.usage", "Specify something to examine.")
+	}
+}
+
+type PutCommand struct{}
+
+func (p PutCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	args := input.Args
+	if len(args) == 3 && args[1] == "in" {
+		args = []string{args[0], args[2]}
+	}
+	if len(args) != 2 {
+		return player.text("put.usage", "Specify an item and where to put it (e.g., put plate in dishwasher).")
+	}
+	return player.PutIn(args[0], args[1], ConsoleDisplay{})
+}
+
+type OpenCommand struct{}
+
+func (o OpenCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) > 0 {
+		return player.Open(input.Args[0], ConsoleDisplay{})
+	} else {
+		return player.text("open.usage", "Specify something to open.")
+	}
+}
+
+type CloseCommand struct{}
+
+func (c CloseCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
 	if len(input.Args) > 0 {
-		return game.player.Take(input.Args[0], ConsoleDisplay{})
+		return player.Close(input.Args[0], ConsoleDisplay{})
 	} else {
-		return "Specify an item to take."
+		return player.text("close.usage", "Specify something to close.")
 	}
 }
 
 type DropCommand struct{}
 
-func (d DropCommand) Execute(input PlayerInput, game *Game) string {
+func (d DropCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
 	if len(input.Args) > 0 {
-		return game.player.Drop(input.Args[0], ConsoleDisplay{})
+		return player.Drop(input.Args[0], ConsoleDisplay{})
 	} else {
-		return "Specify an item to drop."
+		return player.text("drop.usage", "Specify an item to drop.")
 	}
 }
 
 type InventoryCommand struct{}
 
-func (i InventoryCommand) Execute(input PlayerInput, game *Game) string {
+func (i InventoryCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
-	return game.player.ShowInventory(ConsoleDisplay{})
+	return player.ShowInventory(ConsoleDisplay{})
 }
 
 type ApproachCommand struct{}
 
-func (a ApproachCommand) Execute(input PlayerInput, game *Game) string {
+func (a ApproachCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
 	if len(input.Args) > 0 {
 
-		returnValue := game.player.Approach(input.Args[0], ConsoleDisplay{})
+		returnValue := player.Approach(input.Args[0], ConsoleDisplay{})
 
 		if !game.unlockComputer.Triggered {
-			if game.player.CurrentEntity != nil && game.player.CurrentEntity.Name == "computer" {
-				game.isAttemptingPassword = true
+			if player.CurrentEntity != nil && player.CurrentEntity.Name == "computer" {
+				player.isAttemptingPassword = true
 			}
 		}
-		if game.player.CurrentEntity != nil && game.player.CurrentEntity.Name == "terminal" {
-			game.isAttemptingTerminal = true
+		if player.CurrentEntity != nil && player.CurrentEntity.Name == "terminal" {
+			player.isAttemptingTerminal = true
 		}
 
 		return returnValue
 
 	} else {
-		return "Specify an entity to approach."
+		return player.text("approach.usage", "Specify an entity to approach.")
 	}
 }
 
 type UseCommand struct{}
 
-func (u UseCommand) Execute(input PlayerInput, game *Game) string {
+func (u UseCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
+	if len(input.Args) == 3 && input.Args[1] == "on" && player.CurrentRoom.Entities[input.Args[2]] == nil {
+		return game.combine(player, []string{input.Args[0], input.Args[2]})
+	}
+	if len(input.Args) == 3 && input.Args[1] == "on" {
+		return player.Use(input.Args[0], input.Args[2], ConsoleDisplay{})
+	}
 	if len(input.Args) > 0 {
-		if game.player.CurrentEntity == nil {
-			return game.player.Use(input.Args[0], "unspecified_entity", ConsoleDisplay{})
+		if player.CurrentEntity == nil {
+			return player.Use(input.Args[0], "unspecified_entity", ConsoleDisplay{})
 		} else {
-			return game.player.Use(input.Args[0], game.player.CurrentEntity.Name, ConsoleDisplay{})
+			return player.Use(input.Args[0], player.CurrentEntity.Name, ConsoleDisplay{})
 		}
 	} else {
-		return "Specify an item to use."
+		return player.text("use.usage", "Specify an item to use.")
 	}
 }
 
 type LeaveCommand struct{}
 
-func (l LeaveCommand) Execute(input PlayerInput, game *Game) string {
-	return game.player.Leave()
+func (l LeaveCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+	return player.Leave()
 }
 
 type MoveCommand struct{}
 
-func (m MoveCommand) Execute(input PlayerInput, game *Game) string {
+func (m MoveCommand) Execute(input PlayerInput, game *Game, player *Player) string {
 
-	if _, ok := game.player.Inventory["lanyard"]; ok {
-		if len(input.Args) > 0 {
-			return game.player.Move(input.Args[0], ConsoleDisplay{})
-		} else {
-			return "Specify a direction to move (e.g., north)."
-		}
+	if len(input.Args) > 0 {
+		return player.Move(input.Args[0], ConsoleDisplay{})
 	} else {
-		return "Doors are shut for you if you don't have a lanyard."
+		return player.text("move.usage", "Specify a direction to move (e.g., north).")
 	}
 }
 
+type UnlockCommand struct{}
+
+func (u UnlockCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	switch len(input.Args) {
+	case 1:
+		return game.unlock(player, input.Args[0], "")
+	case 2:
+		return game.unlock(player, input.Args[0], input.Args[1])
+	default:
+		return player.text("unlock.usage", "Specify a direction to unlock (e.g., unlock south).")
+	}
+}
+
+type LockCommand struct{}
+
+func (l LockCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if len(input.Args) > 0 {
+		return game.lock(player, input.Args[0])
+	} else {
+		return player.text("lock.usage", "Specify a direction to lock (e.g., lock south).")
+	}
+}
+
+type GiveCommand struct{}
+
+func (g GiveCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	args := input.Args
+	if len(args) == 3 && args[1] == "to" {
+		args = []string{args[0], args[2]}
+	}
+	if len(args) != 2 {
+		return player.text("give.usage", "Specify an item and who to give it to (e.g., give tea to rosie).")
+	}
+	itemName, target := args[0], args[1]
+
+	for _, other := range game.otherPlayers(player, true) {
+		if other.Name == target {
+			return player.GiveToPlayer(itemName, other, ConsoleDisplay{})
+		}
+	}
+	if entity, ok := player.CurrentRoom.Entities[target]; ok && !entity.Hidden {
+		return player.GiveToEntity(itemName, entity, ConsoleDisplay{})
+	}
+	return player.text("give.nobody", "There is no %s here to give %s to.\n", escapeMarkup(target), escapeMarkup(itemName))
+}
+
+type TimeCommand struct{}
+
+func (t TimeCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	return player.text("time", "It's %s, turn %d.\nThe building locks down at %s.\n", game.clock, game.clock.Turn, formatTimeOfDay(lockdownAt))
+}
+
 type MapCommand struct{}
 
-func (m MapCommand) Execute(input PlayerInput, game *Game) string {
+func (m MapCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if player.accessible() {
+		return player.ShowMap(ConsoleDisplay{})
+	}
+	return player.DrawMap(game.staffRoom) + "\n" + player.ShowMap(ConsoleDisplay{})
+}
+
+type SayCommand struct{}
 
-	return game.player.ShowMap(ConsoleDisplay{})
+func (s SayCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if game.chatDisabled {
+		return player.text("chat.disabled", "Chat is disabled in this world.")
+	}
+	if len(input.Args) == 0 {
+		return player.text("say.usage", "Specify something to say.")
+	}
+	text := strings.Join(input.Args, " ")
+	game.chat(player, "say", game.otherPlayers(player, true), text)
+	return player.text("say.done", "You say: %s\n", escapeMarkup(text))
+}
+
+type ShoutCommand struct{}
+
+func (s ShoutCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if game.chatDisabled {
+		return player.text("chat.disabled", "Chat is disabled in this world.")
+	}
+	if len(input.Args) == 0 {
+		return player.text("shout.usage", "Specify something to shout.")
+	}
+	text := strings.Join(input.Args, " ")
+	game.chat(player, "shout", game.otherPlayers(player, false), text)
+	return player.text("shout.done", "You shout: %s\n", escapeMarkup(text))
+}
+
+type WhisperCommand struct{}
+
+func (w WhisperCommand) Execute(input PlayerInput, game *Game, player *Player) string {
+
+	if game.chatDisabled {
+		return player.text("chat.disabled", "Chat is disabled in this world.")
+	}
+	if len(input.Args) < 2 {
+		return player.text("whisper.usage", "Specify a player and something to whisper to them.")
+	}
+	for _, other := range game.otherPlayers(player, true) {
+		if other.Name == input.Args[0] {
+			text := strings.Join(input.Args[1:], " ")
+			game.chat(player, "whisper", []*Player{other}, text)
+			return player.text("whisper.done", "You whisper to %s: %s\n", escapeMarkup(other.Name), escapeMarkup(text))
+		}
+	}
+	return player.text("whisper.nobody", "%s is not here to whisper to.\n", escapeMarkup(input.Args[0]))
 }
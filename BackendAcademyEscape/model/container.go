@@ -0,0 +1,273 @@
+package model
+
+import (
+	"strings"
+)
+
+// ContainerOrder decides which item can come out of a container next.
+type ContainerOrder string
+
+const (
+	// Unordered containers let any item out.
+	Unordered ContainerOrder = "unordered"
+	// Stack containers only let out the last item put in.
+	Stack ContainerOrder = "stack"
+	// Queue containers only let out the first item put in.
+	Queue ContainerOrder = "queue"
+)
+
+// Container holds items. It can stand in a room, be part of an entity such
+// as a dishwasher, or be carried as an item such as a bag.
+type Container struct {
+	Name        string
+	Description string
+	// Capacity is the most items the container holds. Zero means no limit.
+	Capacity int
+	Order    ContainerOrder
+	// Accepts lists the only items that can be put in, if set.
+	Accepts []string
+	// Closable containers can be opened and closed, and keep their items
+	// in while they are Closed.
+	Closable bool
+	Closed   bool
+	Hidden   bool
+	// Fragile containers break when anything but the next item is taken
+	// out, showing Spill and losing the game.
+	Fragile bool
+	Spill   string
+	// Items are kept in the order they were put in.
+	Items  []*Item
+	Detail *Detail
+}
+
+func (c *Container) find(itemName string) (int, *Item) {
+	for i, item := range c.Items {
+		if item.Name == itemName {
+			return i, item
+		}
+	}
+	return -1, nil
+}
+
+// next reports whether the item at index i is the one that can come out.
+func (c *Container) next(i int) bool {
+	switch c.Order {
+	case Stack:
+		return i == len(c.Items)-1
+	case Queue:
+		return i == 0
+	default:
+		return true
+	}
+}
+
+func (c *Container) accepts(itemName string) bool {
+	if len(c.Accepts) == 0 {
+		return true
+	}
+	for _, name := range c.Accepts {
+		if name == itemName {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Container) isFull() bool {
+	return c.Capacity > 0 && len(c.Items) >= c.Capacity
+}
+
+func (c *Container) remove(i int) *Item {
+	item := c.Items[i]
+	c.Items = append(c.Items[:i:i], c.Items[i+1:]...)
+	return item
+}
+
+// weight is what the container's items weigh, including any containers
+// inside it.
+func (c *Container) weight() int {
+	total := 0
+	for _, item := range c.Items {
+		total += item.totalWeight()
+	}
+	return total
+}
+
+// holds reports whether item is the container itself, or somewhere inside it.
+func (c *Container) holds(item *Item) bool {
+	if item.Container == c {
+		return true
+	}
+	for _, inside := range c.Items {
+		if inside.Container != nil && inside.Container.holds(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// contents lists the items in the order they come out, the next one first.
+func (c *Container) contents(p *Player) string {
+	if c.Closed {
+		return p.text("contents.closed", "closed")
+	}
+	if len(c.Items) == 0 {
+		return p.text("contents.empty", "empty")
+	}
+	names := make([]string, len(c.Items))
+	for i, item := range c.Items {
+		names[i] = item.Name
+	}
+	if c.Order == Stack {
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// findContainer looks for a container the player can reach: one standing in
+// the room, part of an entity in the room, or carried. It also reports
+// whether the container is carried, since the weight of what goes in and
+// out of it is then carried too.
+func (p *Player) findContainer(name string) (*Container, bool) {
+	if container, ok := p.CurrentRoom.Containers[name]; ok && !container.Hidden {
+		return container, false
+	}
+	if entity, ok := p.CurrentRoom.Entities[name]; ok && !entity.Hidden && entity.Container != nil {
+		return entity.Container, false
+	}
+	if item, ok := p.Inventory[name]; ok && item.Container != nil {
+		return item.Container, true
+	}
+	return nil, false
+}
+
+// PutIn moves an item from the inventory into a container. Putting an item
+// into an entity's container counts as using it on the entity.
+func (p *Player) PutIn(itemName string, containerName string, display Display) string {
+	item, ok := p.Inventory[itemName]
+	if !ok {
+		return show(display, p.text("error.item.missing", "You don't have %s.\n", escapeMarkup(itemName)))
+	}
+	container, carried := p.findContainer(containerName)
+	switch {
+	case container == nil:
+		return show(display, p.text("storage.missing", "There is no %s here.\n", escapeMarkup(containerName)))
+	case container.holds(item):
+		return show(display, p.text("put.itself", "You can't put %s inside itself.\n", escapeMarkup(itemName)))
+	case container.Closed:
+		return show(display, p.text("storage.closed", "%s is closed.\n", escapeMarkup(containerName)))
+	case !container.accepts(itemName):
+		return show(display, p.text("put.refused", "%s won't take %s.\n", escapeMarkup(containerName), escapeMarkup(itemName)))
+	case container.isFull():
+		return show(display, p.text("put.full", "%s is full.\n", escapeMarkup(containerName)))
+	}
+
+	if carried {
+		delete(p.Inventory, itemName)
+		delete(p.Equipped, itemName)
+	} else {
+		p.release(item)
+	}
+	container.Items = append(container.Items, item)
+	p.emit(GameEvent{Type: ItemStored, Item: itemName, Room: p.CurrentRoom.Name, Target: containerName})
+
+	// Taking an item back out and putting it in again doesn't repeat the
+	// event; it only happens the first time.
+	for _, interaction := range p.interactions() {
+		if interactionIsValid(interaction, itemName, containerName) && !interaction.Event.Triggered {
+			return show(display, p.TriggerEvent(interaction.Event))
+		}
+	}
+	return show(display, p.text("put.done", "You put %s in %s.\n", escapeMarkup(itemName), escapeMarkup(containerName)))
+}
+
+// TakeFrom moves an item out of a container into the inventory, if it is
+// the item the container lets out next.
+func (p *Player) TakeFrom(itemName string, containerName string, display Display) string {
+	container, carried := p.findContainer(containerName)
+	if container == nil {
+		return show(display, p.text("storage.missing", "There is no %s here.\n", escapeMarkup(containerName)))
+	}
+	if container.Closed {
+		return show(display, p.text("storage.closed", "%s is closed.\n", escapeMarkup(containerName)))
+	}
+	i, item := container.find(itemName)
+	if item == nil {
+		return show(display, p.text("take.notinside", "There is no %s in %s.\n", escapeMarkup(itemName), escapeMarkup(containerName)))
+	}
+	err := p.canCarry(item)
+	if carried {
+		err = p.canCarryOutOf(container, i)
+	}
+	if err != nil {
+		return show(display, p.capacityMessage(err))
+	}
+	switch {
+	case !container.next(i) && container.Fragile:
+		p.brokePlates = true
+		return show(display, container.Spill)
+	case !container.next(i):
+		return show(display, p.text("take.blocked", "You can't get %s out of %s without moving other things first.\n", escapeMarkup(itemName), escapeMarkup(containerName)))
+	}
+
+	container.remove(i)
+	if carried {
+		p.Inventory[itemName] = item
+	} else {
+		p.carry(item)
+	}
+	p.emit(GameEvent{Type: ItemTaken, Item: itemName, Room: p.CurrentRoom.Name, Target: containerName})
+	return show(display, p.text("take.done", "%s has been added to your inventory.\n", escapeMarkup(itemName)))
+}
+
+// containerHolding finds an open container in the room with the item in it,
+// so that take works without naming the container.
+func (p *Player) containerHolding(itemName string) string {
+	for _, name := range sortedNames(p.CurrentRoom.Containers) {
+		container := p.CurrentRoom.Containers[name]
+		if _, item := container.find(itemName); item != nil && !container.Hidden && !container.Closed {
+			return name
+		}
+	}
+	return ""
+}
+
+// Open opens a closable container.
+func (p *Player) Open(containerName string, display Display) string {
+	return p.setClosed(containerName, false, display)
+}
+
+// Close closes a closable container.
+func (p *Player) Close(containerName string, display Display) string {
+	return p.setClosed(containerName, true, display)
+}
+
+func (p *Player) setClosed(containerName string, closed bool, display Display) string {
+	container, _ := p.findContainer(containerName)
+	verb := map[bool]string{true: "close", false: "open"}[closed]
+	state := map[bool]string{true: "closed", false: "open"}[closed]
+	switch {
+	case container == nil:
+		return show(display, p.text("storage.missing", "There is no %s here.\n", escapeMarkup(containerName)))
+	case !container.Closable:
+		return show(display, p.text("storage.cant."+verb, "You can't %s %s.\n", verb, escapeMarkup(containerName)))
+	case container.Closed == closed:
+		return show(display, p.text("storage.already."+state, "%s is already %s.\n", escapeMarkup(containerName), state))
+	}
+	container.Closed = closed
+	return show(display, p.text("storage."+verb, "You %s %s.\n", verb, escapeMarkup(containerName)))
+}
+
+// AddContainer puts a container in the room with the given name.
+func (game *Game) AddContainer(room string, container *Container) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	in := game.roomNamed(room)
+	if in == nil {
+		return ErrUnknownRoom
+	}
+	in.Containers[container.Name] = container
+	return nil
+}
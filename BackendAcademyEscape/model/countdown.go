@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNoCountdown = errors.New("this world has no time limit")
+
+// countdown is a wall-clock time limit for a world. While it runs the time
+// left is worked out from deadline; while paused it is kept in remaining.
+type countdown struct {
+	limit     time.Duration
+	deadline  time.Time
+	remaining time.Duration
+	paused    bool
+}
+
+func (c *countdown) left() time.Duration {
+	if c.paused {
+		return c.remaining
+	}
+	return max(time.Until(c.deadline), 0)
+}
+
+// CountdownState is a snapshot of a world's time limit.
+type CountdownState struct {
+	Limit     time.Duration
+	Remaining time.Duration
+	Paused    bool
+}
+
+// SetTimeLimit starts a countdown. When it runs out the game ends for every
+// player in the world.
+func (game *Game) SetTimeLimit(limit time.Duration) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.countdown = &countdown{limit: limit, deadline: time.Now().Add(limit)}
+}
+
+// Countdown reports the world's time limit, if it has one.
+func (game *Game) Countdown() (CountdownState, bool) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.expireIfDue()
+	return game.countdownState()
+}
+
+func (game *Game) countdownState() (CountdownState, bool) {
+	if game.countdown == nil {
+		return CountdownState{}, false
+	}
+	return CountdownState{Limit: game.countdown.limit, Remaining: game.countdown.left(), Paused: game.countdown.paused}, true
+}
+
+// PauseCountdown stops the clock until ResumeCountdown is called.
+func (game *Game) PauseCountdown() (CountdownState, error) {
+	return game.changeCountdown(func(c *countdown) {
+		if !c.paused {
+			c.remaining = c.left()
+			c.paused = true
+		}
+	})
+}
+
+// ResumeCountdown starts a paused clock again.
+func (game *Game) ResumeCountdown() (CountdownState, error) {
+	return game.changeCountdown(func(c *countdown) {
+		if c.paused {
+			c.deadline = time.Now().Add(c.remaining)
+			c.paused = false
+		}
+	})
+}
+
+// ExtendCountdown gives the players more time.
+func (game *Game) ExtendCountdown(by time.Duration) (CountdownState, error) {
+	return game.changeCountdown(func(c *countdown) {
+		c.limit += by
+		c.remaining += by
+		c.deadline = c.deadline.Add(by)
+	})
+}
+
+// ResetCountdown gives the players the whole time limit again, keeping the
+// clock paused if it was.
+func (game *Game) ResetCountdown() (CountdownState, error) {
+	return game.changeCountdown(func(c *countdown) {
+		c.remaining = c.limit
+		c.deadline = time.Now().Add(c.limit)
+	})
+}
+
+func (game *Game) changeCountdown(change func(c *countdown)) (CountdownState, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.expireIfDue()
+	if game.countdown == nil {
+		return CountdownState{}, ErrNoCountdown
+	}
+	if game.gameOver {
+		return CountdownState{}, ErrGameOver
+	}
+	change(game.countdown)
+	state, _ := game.countdownState()
+	return state, nil
+}
+
+// CheckTimeout ends the game if its time limit has run out, and reports
+// whether it did. It is checked on every command, and can be called from a
+// timer to end the game while nobody is typing.
+func (game *Game) CheckTimeout() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	return game.expireIfDue()
+}
+
+// expireIfDue must be called with the game locked.
+func (game *Game) expireIfDue() bool {
+	if game.countdown == nil || game.gameOver || game.countdown.paused || game.countdown.left() > 0 {
+		return false
+	}
+	game.gameOver = true
+	// Every player is told in their own language. The event is shared by
+	// the whole world, so its message is the English text.
+	message := "Time's up! The doors stay locked and the hack day is over."
+	for _, player := range game.players {
+		if !player.exited {
+			player.hear(player.text("countdown.over", message) + "\n\n")
+		}
+	}
+	game.emit(GameEvent{Type: GameEnded, Event: "timeout", Message: message})
+	return true
+}
+
+// remainingSeconds is the time left rounded up to the second, for responses.
+func (game *Game) remainingSeconds() *int {
+	if game.countdown == nil {
+		return nil
+	}
+	left := game.countdown.left()
+	seconds := int((left + time.Second - 1) / time.Second)
+	return &seconds
+}
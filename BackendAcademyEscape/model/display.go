@@ -1,11 +1,202 @@
 package model
 
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"strings"
+)
+
 type Display interface {
 	Show(text string) string
 }
 
+// MarkupDisplay is a Display that is given responses with their markup.
+// Any other Display is given plain text.
+type MarkupDisplay interface {
+	Display
+	ShowMarkup(text string) string
+}
+
+// show hands marked up text to a display, without the markup if it doesn't
+// understand it.
+func show(display Display, text string) string {
+	if markup, ok := display.(MarkupDisplay); ok {
+		return markup.ShowMarkup(text)
+	}
+	return display.Show(PlainText(text))
+}
+
+// ConsoleDisplay keeps the markup, so that a response can be rendered for
+// the player once it is complete.
 type ConsoleDisplay struct{}
 
 func (c ConsoleDisplay) Show(text string) string {
 	return text
 }
+
+func (c ConsoleDisplay) ShowMarkup(text string) string {
+	return text
+}
+
+// PlainDisplay shows responses as plain text. Players see this unless they
+// choose another.
+type PlainDisplay struct{}
+
+func (PlainDisplay) Show(text string) string {
+	return PlainText(text)
+}
+
+// ANSIDisplay colours headings and names for terminals.
+type ANSIDisplay struct{}
+
+var ansiStyles = map[string]string{
+	HeadingNode:  "\x1b[1m",
+	CodeNode:     "\x1b[2m",
+	EmphasisNode: "\x1b[3m",
+	ItemNode:     "\x1b[36m",
+	EntityNode:   "\x1b[33m",
+	PlayerNode:   "\x1b[35m",
+	ExitNode:     "\x1b[32m",
+}
+
+func (d ANSIDisplay) Show(text string) string {
+	var out strings.Builder
+	d.write(&out, ParseMarkup(text), "")
+	return out.String()
+}
+
+// write shows nodes inside the given style, restoring it after each styled
+// node.
+func (d ANSIDisplay) write(out *strings.Builder, nodes []Node, style string) {
+	for _, node := range nodes {
+		switch node.Type {
+		case TextNode:
+			out.WriteString(node.Text)
+		case ListItemNode:
+			out.WriteString("- ")
+			d.write(out, node.Children, style)
+		default:
+			inner := style + ansiStyles[node.Type]
+			out.WriteString(inner)
+			d.write(out, node.Children, inner)
+			out.WriteString("\x1b[0m" + style)
+		}
+	}
+}
+
+// HTMLDisplay shows responses as HTML for the web client. Names are links
+// carrying the command that acts on them in data-command.
+type HTMLDisplay struct{}
+
+var htmlCommands = map[string]string{
+	ItemNode:   "examine",
+	EntityNode: "approach",
+	ExitNode:   "move",
+}
+
+func (d HTMLDisplay) Show(text string) string {
+	var out strings.Builder
+	d.write(&out, groupLists(ParseMarkup(text)))
+	return out.String()
+}
+
+func (d HTMLDisplay) write(out *strings.Builder, nodes []Node) {
+	for i, node := range nodes {
+		switch node.Type {
+		case TextNode:
+			text := node.Text
+			if i > 0 && isBlock(nodes[i-1]) {
+				text = strings.TrimPrefix(text, "\n")
+			}
+			if i < len(nodes)-1 && isBlock(nodes[i+1]) {
+				text = strings.TrimSuffix(text, "\n")
+			}
+			out.WriteString(strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n"))
+		case HeadingNode:
+			d.wrap(out, "<h3>", node.Children, "</h3>")
+		case ListNode:
+			d.wrap(out, "<ul>", node.Children, "</ul>")
+		case ListItemNode:
+			d.wrap(out, "<li>", node.Children, "</li>")
+		case CodeNode:
+			out.WriteString("<pre><code>" + html.EscapeString(plainOf(node.Children)) + "</code></pre>")
+		case EmphasisNode:
+			d.wrap(out, "<em>", node.Children, "</em>")
+		case PlayerNode:
+			d.wrap(out, `<span class="player">`, node.Children, "</span>")
+		default:
+			name := plainOf(node.Children)
+			d.wrap(out, `<a class="`+node.Type+`" data-command="`+html.EscapeString(htmlCommands[node.Type]+" "+name)+`">`, node.Children, "</a>")
+		}
+	}
+}
+
+func (d HTMLDisplay) wrap(out *strings.Builder, open string, children []Node, close string) {
+	out.WriteString(open)
+	d.write(out, children)
+	out.WriteString(close)
+}
+
+func isBlock(node Node) bool {
+	return node.Type == HeadingNode || node.Type == ListNode || node.Type == CodeNode
+}
+
+// plainOf is the text of nodes without their markup.
+func plainOf(nodes []Node) string {
+	var out strings.Builder
+	writePlain(&out, nodes)
+	return out.String()
+}
+
+// JSONDisplay shows responses as a JSON array of nodes, with list items
+// grouped into lists, for clients that lay text out themselves.
+type JSONDisplay struct{}
+
+func (JSONDisplay) Show(text string) string {
+	nodes := groupLists(ParseMarkup(text))
+	if nodes == nil {
+		nodes = []Node{}
+	}
+	encoded, _ := json.Marshal(nodes)
+	return string(encoded)
+}
+
+// Displays are the displays a player can choose by name.
+var Displays = map[string]Display{
+	"plain":      PlainDisplay{},
+	"ansi":       ANSIDisplay{},
+	"html":       HTMLDisplay{},
+	"json":       JSONDisplay{},
+	"accessible": AccessibleDisplay{},
+}
+
+var ErrUnknownDisplay = errors.New("no display with that name")
+
+// SetDisplay changes how a player's responses are rendered, by the name of
+// one of the Displays.
+func (game *Game) SetDisplay(playerID string, name string) error {
+	display, ok := Displays[name]
+	if !ok {
+		return ErrUnknownDisplay
+	}
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return ErrUnknownPlayer
+	}
+	player.Display = display
+	return nil
+}
+
+func (p *Player) display() Display {
+	if p.Display == nil {
+		return PlainDisplay{}
+	}
+	if accessible, ok := p.Display.(AccessibleDisplay); ok {
+		accessible.reader = p
+		return accessible
+	}
+	return p.Display
+}
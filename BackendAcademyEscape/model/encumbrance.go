@@ -0,0 +1,213 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encumbrance decides how much a player can carry. Every player keeps
+// CarriedWeight and AvailableWeight up to date whatever the policy, so
+// policies only have to say whether one more item fits.
+type Encumbrance interface {
+	// Check returns a *CapacityError if the player can't carry item as
+	// well as what they carry already. Items already carried aren't counted
+	// twice.
+	Check(p *Player, item *Item) error
+	// Summary is the first line of the inventory, showing what's left.
+	Summary(p *Player) string
+}
+
+// CapacityError is returned when carrying an item would go over a limit.
+type CapacityError struct {
+	Limit string
+	Item  string
+	Need  int
+	Free  int
+}
+
+func (e *CapacityError) Error() string {
+	return fmt.Sprintf("carrying %s would go over the %s limit", e.Item, e.Limit)
+}
+
+// Message explains to the player what's in the way.
+func (e *CapacityError) Message() string {
+	switch e.Limit {
+	case "hands":
+		return "Your hands are full. Put something in a bag, equip it or drop it first.\n"
+	case "volume":
+		return fmt.Sprintf("%s won't fit: it takes %d space and you have %d left.\n", e.Item, e.Need, e.Free)
+	default:
+		return fmt.Sprintln("Weight limit reached! Please drop an item before taking more.")
+	}
+}
+
+// WeightLimit is the default policy: items fit while their weight is no
+// more than the player's AvailableWeight.
+type WeightLimit struct{}
+
+func (WeightLimit) Check(p *Player, item *Item) error {
+	if p.Inventory[item.Name] == item || item.totalWeight() <= p.AvailableWeight {
+		return nil
+	}
+	return &CapacityError{Limit: "weight", Item: item.Name, Need: item.totalWeight(), Free: p.AvailableWeight}
+}
+
+func (WeightLimit) Summary(p *Player) string {
+	return p.text("capacity.available", "Available space: %d\n", p.AvailableWeight)
+}
+
+// Limits caps the weight and volume of what a player carries, and how many
+// items they hold in their hands. Equipped items and items in bags don't
+// need a hand. A zero limit is no limit.
+type Limits struct {
+	Weight int
+	Volume int
+	Hands  int
+}
+
+func (l Limits) Check(p *Player, item *Item) error {
+	weight, volume, held := l.carrying(p, item)
+	switch {
+	case l.Weight > 0 && weight+item.totalWeight() > l.Weight:
+		return &CapacityError{Limit: "weight", Item: item.Name, Need: item.totalWeight(), Free: l.Weight - weight}
+	case l.Volume > 0 && volume+item.Volume > l.Volume:
+		return &CapacityError{Limit: "volume", Item: item.Name, Need: item.Volume, Free: l.Volume - volume}
+	case l.Hands > 0 && !p.Equipped[item.Name] && held+1 > l.Hands:
+		return &CapacityError{Limit: "hands", Item: item.Name, Need: 1, Free: l.Hands - held}
+	}
+	return nil
+}
+
+// carrying adds up what the player carries, leaving out except.
+func (l Limits) carrying(p *Player, except *Item) (weight int, volume int, held int) {
+	for _, item := range p.Inventory {
+		if item == except {
+			continue
+		}
+		weight += item.totalWeight()
+		volume += item.Volume
+		if !p.Equipped[item.Name] {
+			held++
+		}
+	}
+	return weight, volume, held
+}
+
+func (l Limits) Summary(p *Player) string {
+	weight, volume, held := l.carrying(p, nil)
+	var parts []string
+	if l.Weight > 0 {
+		parts = append(parts, p.text("capacity.carrying.weight", "weight %d/%d", weight, l.Weight))
+	}
+	if l.Volume > 0 {
+		parts = append(parts, p.text("capacity.carrying.volume", "volume %d/%d", volume, l.Volume))
+	}
+	if l.Hands > 0 {
+		parts = append(parts, p.text("capacity.carrying.hands", "hands %d/%d", held, l.Hands))
+	}
+	return p.text("capacity.carrying", "Carrying: %s\n", strings.Join(parts, ", "))
+}
+
+// SetEncumbrance changes what every player in the world can carry, for
+// players already in it and those who join later.
+func (game *Game) SetEncumbrance(policy Encumbrance) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.capacity = policy
+	for _, player := range game.players {
+		player.Capacity = policy
+	}
+}
+
+func (p *Player) encumbrance() Encumbrance {
+	if p.Capacity == nil {
+		return WeightLimit{}
+	}
+	return p.Capacity
+}
+
+// canCarry returns a *CapacityError if item doesn't fit.
+func (p *Player) canCarry(item *Item) error {
+	return p.encumbrance().Check(p, item)
+}
+
+// canCarryOutOf is canCarry for the item at i in a bag the player carries,
+// whose weight is already counted in the bag's. Only the weight is left
+// out, so the item still needs a free hand and room for its volume.
+func (p *Player) canCarryOutOf(container *Container, i int) error {
+	item, items := container.Items[i], container.Items
+	container.Items = append(items[:i:i], items[i+1:]...)
+	p.CarriedWeight -= item.totalWeight()
+	p.AvailableWeight += item.totalWeight()
+	err := p.canCarry(item)
+	container.Items = items
+	p.CarriedWeight += item.totalWeight()
+	p.AvailableWeight -= item.totalWeight()
+	return err
+}
+
+// carry puts an item into the inventory.
+func (p *Player) carry(item *Item) {
+	p.Inventory[item.Name] = item
+	p.CarriedWeight += item.totalWeight()
+	p.AvailableWeight -= item.totalWeight()
+}
+
+// release takes an item out of the inventory, taking it off if it was
+// equipped.
+func (p *Player) release(item *Item) {
+	delete(p.Inventory, item.Name)
+	delete(p.Equipped, item.Name)
+	p.CarriedWeight -= item.totalWeight()
+	p.AvailableWeight += item.totalWeight()
+}
+
+// capacityMessage is what the player is told when err stops them carrying
+// something, in their language.
+func (p *Player) capacityMessage(err error) string {
+	capacity, ok := err.(*CapacityError)
+	if !ok {
+		return err.Error()
+	}
+	translated, ok := p.catalogue.translate(p.Locale, "capacity."+capacity.Limit)
+	switch {
+	case !ok:
+		return capacity.Message()
+	case capacity.Limit == "volume":
+		return fmt.Sprintf(translated, capacity.Item, capacity.Need, capacity.Free)
+	default:
+		return translated
+	}
+}
+
+// Equip wears an item, which frees up a hand.
+func (p *Player) Equip(itemName string, display Display) string {
+	item, ok := p.Inventory[itemName]
+	switch {
+	case !ok:
+		return show(display, p.text("error.item.missing", "You don't have %s.\n", escapeMarkup(itemName)))
+	case !item.Wearable:
+		return show(display, p.text("equip.unwearable", "You can't wear %s.\n", escapeMarkup(itemName)))
+	case p.Equipped[itemName]:
+		return show(display, p.text("equip.worn", "You're already wearing %s.\n", escapeMarkup(itemName)))
+	}
+	if p.Equipped == nil {
+		p.Equipped = make(map[string]bool)
+	}
+	p.Equipped[itemName] = true
+	return show(display, p.text("equip.done", "You put on %s.\n", escapeMarkup(itemName)))
+}
+
+// Unequip takes an item off and holds it, if there's a free hand.
+func (p *Player) Unequip(itemName string, display Display) string {
+	item, ok := p.Inventory[itemName]
+	if !ok || !p.Equipped[itemName] {
+		return show(display, p.text("unequip.missing", "You aren't wearing %s.\n", escapeMarkup(itemName)))
+	}
+	delete(p.Equipped, itemName)
+	if err := p.canCarry(item); err != nil {
+		p.Equipped[itemName] = true
+		return show(display, p.capacityMessage(err))
+	}
+	return show(display, p.text("unequip.done", "You take off %s.\n", itemName))
+}
@@ -4,6 +4,42 @@ type Entity struct {
 	Name        string
 	Description string
 	Hidden      bool
+	Inventory   map[string]*Item
+	Reactions   []*Reaction
+	Container   *Container
+	Detail      *Detail
+}
+
+// Reaction is how an entity responds to being given an item. An accepted
+// item goes into the entity's inventory, and HandsBack names an item from
+// that inventory to give the player in return.
+type Reaction struct {
+	ItemName  string
+	Accept    bool
+	Response  string
+	HandsBack string
+}
+
+func (e *Entity) reactionTo(itemName string) *Reaction {
+	for _, reaction := range e.Reactions {
+		if reaction.ItemName == itemName {
+			return reaction
+		}
+	}
+	return nil
+}
+
+// response is what the entity says to being given the item, in the
+// player's language.
+func (r *Reaction) response(p *Player, entityName string) string {
+	return p.text("reaction."+entityName+"."+r.ItemName, r.Response)
+}
+
+func (e *Entity) receive(item *Item) {
+	if e.Inventory == nil {
+		e.Inventory = make(map[string]*Item)
+	}
+	e.Inventory[item.Name] = item
 }
 
 func (e *Entity) SetDescription(description string) {
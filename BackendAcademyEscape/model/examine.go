@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Detail is what examining something shows, beyond its usual description.
+type Detail struct {
+	// First is shown the first time a player examines it, and Again every
+	// time after that, if set.
+	First string
+	Again string
+	// Layers replace the text once their event has been triggered. When
+	// several have been, the last one listed wins.
+	Layers []Layer
+	// Reveals names hidden items, entities, containers or exits in the same
+	// room that examining it uncovers.
+	Reveals []string
+	// Triggers is an event that examining it sets off.
+	Triggers *Event
+}
+
+// Layer is a description that takes over after a puzzle event.
+type Layer struct {
+	Event string
+	Text  string
+}
+
+// text is what the player is shown for the thing called name, in their
+// language.
+func (d *Detail) text(game *Game, player *Player, name string, seen bool) string {
+	text := player.text("examine."+name, d.First)
+	if seen && d.Again != "" {
+		text = player.text("examine."+name+".again", d.Again)
+	}
+	for _, layer := range d.Layers {
+		if game.eventTriggered(layer.Event) {
+			text = player.text("examine."+name+"."+layer.Event, layer.Text)
+		}
+	}
+	return text
+}
+
+// examine describes the thing the player names: something they carry, an
+// item, container or entity in the room, an exit, or a feature of the room.
+func (game *Game) examine(player *Player, name string) string {
+	room := player.CurrentRoom
+	if item, ok := player.Inventory[name]; ok {
+		return game.describe(player, name, item.Detail, player.describeItem(item)+"\n")
+	}
+	if item, ok := room.Items[name]; ok && !item.Hidden {
+		return game.describe(player, name, item.Detail, player.describeItem(item)+"\n")
+	}
+	if container, ok := room.Containers[name]; ok && !container.Hidden {
+		return game.describe(player, name, container.Detail, fmt.Sprintf("%s (%s)\n", player.text("container."+name, container.Description), container.contents(player)))
+	}
+	if entity, ok := room.Entities[name]; ok && !entity.Hidden {
+		return game.describe(player, name, entity.Detail, player.text("ui.examine.entity", "Nothing catches your eye about %s. Try approaching it.\n", name))
+	}
+	if exit, ok := player.visibleExit(name); ok {
+		fallback := player.text("ui.examine.exit", "The way %s leads to %s.\n", name, exit.To.Name)
+		if !exit.opensFor(player) {
+			fallback = player.text("ui.examine.locked", "The way %s leads to %s, but it's locked.\n", name, exit.To.Name)
+		}
+		return game.describe(player, name, exit.Detail, fallback)
+	}
+	if detail, ok := room.Features[name]; ok {
+		return game.describe(player, name, detail, "")
+	}
+	return player.text("error.examine.missing", "You can't see %s here.\n", escapeMarkup(name))
+}
+
+// describe shows a detail, or fallback when there is none, and uncovers
+// what examining it reveals.
+func (game *Game) describe(player *Player, name string, detail *Detail, fallback string) string {
+	if detail == nil {
+		return fallback
+	}
+	if player.examined == nil {
+		player.examined = make(map[*Detail]bool)
+	}
+	text := player.narrate(detail.text(game, player, name, player.examined[detail]))
+	if text == "" {
+		text = fallback
+	}
+	player.examined[detail] = true
+
+	var revealed []string
+	for _, name := range detail.Reveals {
+		if reveal(player.CurrentRoom, name) {
+			revealed = append(revealed, name)
+		}
+	}
+	if len(revealed) > 0 {
+		text += player.text("ui.examine.revealed", "\n(%s can now be found in the room)\n", strings.Join(revealed, ", "))
+	}
+	if detail.Triggers != nil && !detail.Triggers.Triggered {
+		text += "\n" + player.TriggerEvent(detail.Triggers)
+	}
+	return text
+}
+
+// reveal unhides the named thing in a room, and reports whether it was
+// hidden.
+func reveal(room *Room, name string) bool {
+	if item, ok := room.Items[name]; ok && item.Hidden {
+		item.Hidden = false
+		return true
+	}
+	if entity, ok := room.Entities[name]; ok && entity.Hidden {
+		entity.Hidden = false
+		return true
+	}
+	if container, ok := room.Containers[name]; ok && container.Hidden {
+		container.Hidden = false
+		return true
+	}
+	if exit, ok := room.Exits[name]; ok && exit.Hidden {
+		exit.Hidden = false
+		return true
+	}
+	return false
+}
+
+// AddFeature adds something that can be examined to the room with the given
+// name.
+func (game *Game) AddFeature(room string, name string, detail *Detail) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	in := game.roomNamed(room)
+	if in == nil {
+		return ErrUnknownRoom
+	}
+	if in.Features == nil {
+		in.Features = make(map[string]*Detail)
+	}
+	in.Features[name] = detail
+	return nil
+}
@@ -0,0 +1,152 @@
+package model
+
+import "errors"
+
+var ErrUnknownRoom = errors.New("no such room in this world")
+
+// Exit is a way out of a room. Exits only lead one way: a corridor between
+// two rooms is an exit in each, which can share a Lock so that the door
+// opens and shuts from both sides.
+type Exit struct {
+	To *Room
+	// Lock keeps the exit shut while it is locked. Nil means it never locks.
+	Lock *Lock
+	// LockedDescription is shown when a player is stopped by the lock, and
+	// UnlockedDescription when a player goes through.
+	LockedDescription   string
+	UnlockedDescription string
+	// Hidden exits can't be seen or used until the puzzle event RevealedBy
+	// is triggered.
+	Hidden     bool
+	RevealedBy string
+	Detail     *Detail
+}
+
+// Lock is what it takes to open an exit. Every condition that is set must
+// hold for the unlock command to work.
+type Lock struct {
+	// Item must be carried to unlock or lock the exit.
+	Item string
+	// Event must have been triggered before the exit unlocks.
+	Event string
+	// Password must be given to the unlock command.
+	Password string
+	// Keycard locks let anybody carrying Item straight through, without
+	// unlocking the exit for everybody else.
+	Keycard bool
+	Locked  bool
+}
+
+// opensFor reports whether player can walk through the exit as it is.
+func (e *Exit) opensFor(player *Player) bool {
+	if e.Lock == nil || !e.Lock.Locked {
+		return true
+	}
+	if !e.Lock.Keycard {
+		return false
+	}
+	_, ok := player.Inventory[e.Lock.Item]
+	return ok
+}
+
+func (e *Exit) lockedMessage(player *Player, direction string) string {
+	if e.LockedDescription != "" {
+		return player.text("exit."+player.CurrentRoom.Name+"."+direction+".locked", e.LockedDescription)
+	}
+	return player.text("ui.exit.locked", "The way %s is locked.\n", direction)
+}
+
+// visibleExit finds an exit the player can see from their room.
+func (p *Player) visibleExit(direction string) (*Exit, bool) {
+	exit, ok := p.CurrentRoom.Exits[direction]
+	if !ok || exit.Hidden {
+		return nil, false
+	}
+	return exit, true
+}
+
+// unlock opens a locked exit for everybody, if the player meets the lock's
+// conditions.
+func (game *Game) unlock(player *Player, direction string, password string) string {
+	exit, ok := player.visibleExit(direction)
+	if !ok {
+		return player.text("move.blocked", "You can't go that way!\n")
+	}
+	lock := exit.Lock
+	if lock == nil || !lock.Locked {
+		return player.text("unlock.unlocked", "The way %s isn't locked.\n", direction)
+	}
+	if _, ok := player.Inventory[lock.Item]; lock.Item != "" && !ok {
+		return player.text("unlock.item", "You need %s to unlock the way %s.\n", lock.Item, direction)
+	}
+	if lock.Event != "" && !game.eventTriggered(lock.Event) {
+		return player.text("unlock.event", "The way %s won't unlock yet.\n", direction)
+	}
+	if lock.Password != "" && password == "" {
+		return player.text("unlock.password", "The way %s needs a password (e.g., unlock %s <password>).\n", direction, direction)
+	}
+	if lock.Password != "" && password != lock.Password {
+		return player.text("unlock.wrong", "That's not the right password.\n")
+	}
+	lock.Locked = false
+	player.emit(GameEvent{Type: ExitUnlocked, Room: player.CurrentRoom.Name, Target: direction})
+	return player.text("unlock.done", "You unlock the way %s.\n", direction)
+}
+
+// lock shuts an exit again. Exits whose lock needs an item can only be
+// locked by a player carrying it.
+func (game *Game) lock(player *Player, direction string) string {
+	exit, ok := player.visibleExit(direction)
+	if !ok {
+		return player.text("move.blocked", "You can't go that way!\n")
+	}
+	lock := exit.Lock
+	if lock == nil {
+		return player.text("lock.none", "The way %s has no lock.\n", direction)
+	}
+	if lock.Locked {
+		return player.text("lock.locked", "The way %s is already locked.\n", direction)
+	}
+	if _, ok := player.Inventory[lock.Item]; lock.Item != "" && !ok {
+		return player.text("lock.item", "You need %s to lock the way %s.\n", lock.Item, direction)
+	}
+	lock.Locked = true
+	player.emit(GameEvent{Type: ExitLocked, Room: player.CurrentRoom.Name, Target: direction})
+	return player.text("lock.done", "You lock the way %s.\n", direction)
+}
+
+// revealExits uncovers hidden exits whose event has been triggered, telling
+// the players who can see them.
+func (game *Game) revealExits(t *turn) {
+	for _, room := range game.rooms() {
+		for _, direction := range sortedNames(room.Exits) {
+			if exit := room.Exits[direction]; exit.Hidden && exit.RevealedBy != "" && game.eventTriggered(exit.RevealedBy) {
+				exit.Hidden = false
+				t.tell(game, game.playersIn(room), "ui.exit.opened", "A way %s has opened up.", direction)
+			}
+		}
+	}
+}
+
+// AddExit adds an exit to the world, leading from the room named from to
+// the room named to. Add a second exit for the way back.
+func (game *Game) AddExit(from string, direction string, to string, exit *Exit) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	fromRoom, toRoom := game.roomNamed(from), game.roomNamed(to)
+	if fromRoom == nil || toRoom == nil {
+		return ErrUnknownRoom
+	}
+	exit.To = toRoom
+	fromRoom.Exits[direction] = exit
+	return nil
+}
+
+// connect adds an exit from one room to another and, if back is not empty,
+// the matching exit back again, sharing the same lock.
+func connect(from *Room, direction string, to *Room, back string, lock *Lock, lockedDescription string) {
+	from.Exits[direction] = &Exit{To: to, Lock: lock, LockedDescription: lockedDescription}
+	if back != "" {
+		to.Exits[back] = &Exit{To: from, Lock: lock, LockedDescription: lockedDescription}
+	}
+}
@@ -1,204 +1,432 @@
 package model
 
 import (
-	"academy-adventure-game/global"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 )
 
+// Game is a world shared by one or more players. It is safe for concurrent
+// use: every exported method holds mu while it reads or changes the rooms,
+// items and players, so commands are applied one at a time.
 type Game struct {
-	player *Player
-	// validInteractions         []*Interaction
-	// gameOver                  bool
+	mu                        sync.Mutex
+	players                   []*Player
+	joined                    int
+	validInteractions         []*Interaction
+	gameOver                  bool
+	chatDisabled              bool
+	clock                     Clock
+	commandCosts              map[string]int
+	scheduled                 []*ScheduledEvent
+	recipes                   []*Recipe
+	capacity                  Encumbrance
+	catalogue                 Catalogue
+	npcs                      []*NPC
+	countdown                 *countdown
 	introduction              string
-	introductionShown         bool
 	dishwasherChallengeWon    *Event
 	unlockComputer            *Event
 	remainingPasswordAttempts int
 	computerPassword          string
-	isAttemptingPassword      bool
-	isAttemptingTerminal      bool
-	IsFirstCommand            bool
 	staffRoom                 *Room
 	codingLab                 *Room
 	terminalRoom              *Room
+	kettleApproachedFirst     bool
+	sofaApproachedFirst       bool
+	deskApproachedFirst       bool
+	lanyardEventCompleted     bool
+	listeners                 []listener
+	nextListenerID            int
 }
 
-var kettleApproachedFirst = false
-var sofaApproachedFirst = false
-var deskApproachedFirst = false
-var lanyardEventCompleted = false
+var (
+	ErrNameTaken     = errors.New("another player in this world already has that name")
+	ErrGameOver      = errors.New("the game in this world is over")
+	ErrUnknownPlayer = errors.New("no such player in this world")
+)
 
 var Commands = map[string]Command{
-	"look":      LookCommand{},
-	"exit":      ExitCommand{},
-	"commands":  CommandsCommand{},
-	"take":      TakeCommand{},
-	"drop":      DropCommand{},
-	"inventory": InventoryCommand{},
-	"approach":  ApproachCommand{},
-	"use":       UseCommand{},
-	"leave":     LeaveCommand{},
-	"move":      MoveCommand{},
-	"map":       MapCommand{},
+	"look":          LookCommand{},
+	"exit":          ExitCommand{},
+	"commands":      CommandsCommand{},
+	"take":          TakeCommand{},
+	"drop":          DropCommand{},
+	"inventory":     InventoryCommand{},
+	"approach":      ApproachCommand{},
+	"use":           UseCommand{},
+	"leave":         LeaveCommand{},
+	"move":          MoveCommand{},
+	"map":           MapCommand{},
+	"say":           SayCommand{},
+	"shout":         ShoutCommand{},
+	"whisper":       WhisperCommand{},
+	"give":          GiveCommand{},
+	"time":          TimeCommand{},
+	"unlock":        UnlockCommand{},
+	"lock":          LockCommand{},
+	"put":           PutCommand{},
+	"open":          OpenCommand{},
+	"close":         CloseCommand{},
+	"examine":       ExamineCommand{},
+	"combine":       CombineCommand{},
+	"equip":         EquipCommand{},
+	"unequip":       UnequipCommand{},
+	"language":      LanguageCommand{},
+	"verbose":       VerboseCommand{},
+	"brief":         BriefCommand{},
+	"accessibility": AccessibilityCommand{},
 }
 
-// func (game *Game) GetAvailableActions(command string) GameActions {
-// 	return GameActions{ Actions:[]string{"tea"}}
-// }
-
+// GetAvailableActions lists the arguments the first player could give to
+// command, for single-player games.
 func (game *Game) GetAvailableActions(command string) GameActions {
+	actions, _ := game.GetAvailableActionsFor(game.firstPlayerID(), command)
+	return actions
+}
+
+// GetAvailableActionsFor lists the arguments the given player could give to
+// command from where they are standing.
+func (game *Game) GetAvailableActionsFor(playerID string, command string) (GameActions, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return GameActions{}, ErrUnknownPlayer
+	}
+
 	gameActions := GameActions{Actions: []string{}}
 	switch command {
 	case "use":
-		for _, item := range game.player.Inventory {
-			gameActions.Actions = append(gameActions.Actions, item.Name)
+		for _, name := range sortedNames(player.Inventory) {
+			gameActions.Actions = append(gameActions.Actions, name)
+		}
+	case "combine":
+		for _, name := range sortedNames(player.Inventory) {
+			gameActions.Actions = append(gameActions.Actions, name)
+		}
+		for _, name := range sortedNames(player.CurrentRoom.Items) {
+			if !player.CurrentRoom.Items[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
+			}
 		}
-	case "drop":
-		for _, item := range game.player.Inventory {
-			gameActions.Actions = append(gameActions.Actions, item.Name)
+	case "drop", "give", "put", "equip", "unequip":
+		for _, name := range sortedNames(player.Inventory) {
+			gameActions.Actions = append(gameActions.Actions, name)
 		}
 	case "approach":
-		for _, entity := range game.player.CurrentRoom.Entities {
-			if !entity.Hidden {
-				gameActions.Actions = append(gameActions.Actions, entity.Name)
+		for _, name := range sortedNames(player.CurrentRoom.Entities) {
+			if !player.CurrentRoom.Entities[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
 			}
 		}
 	case "take":
-		for _, item := range game.player.CurrentRoom.Items {
-			if !item.Hidden{
-			gameActions.Actions = append(gameActions.Actions, item.Name)
+		for _, name := range sortedNames(player.CurrentRoom.Items) {
+			if !player.CurrentRoom.Items[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
+			}
+		}
+		for _, name := range sortedNames(player.CurrentRoom.Containers) {
+			if container := player.CurrentRoom.Containers[name]; !container.Hidden && !container.Closed {
+				for _, item := range container.Items {
+					gameActions.Actions = append(gameActions.Actions, item.Name)
+				}
+			}
+		}
+	case "examine":
+		for _, name := range sortedNames(player.Inventory) {
+			gameActions.Actions = append(gameActions.Actions, name)
+		}
+		for _, name := range sortedNames(player.CurrentRoom.Items) {
+			if !player.CurrentRoom.Items[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
+			}
+		}
+		for _, name := range sortedNames(player.CurrentRoom.Entities) {
+			if !player.CurrentRoom.Entities[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
+			}
+		}
+		for _, name := range sortedNames(player.CurrentRoom.Containers) {
+			if !player.CurrentRoom.Containers[name].Hidden {
+				gameActions.Actions = append(gameActions.Actions, name)
+			}
+		}
+		for _, direction := range sortedNames(player.CurrentRoom.Exits) {
+			if !player.CurrentRoom.Exits[direction].Hidden {
+				gameActions.Actions = append(gameActions.Actions, direction)
 			}
 		}
+		for _, name := range sortedNames(player.CurrentRoom.Features) {
+			gameActions.Actions = append(gameActions.Actions, name)
+		}
+	case "language":
+		gameActions.Actions = append(gameActions.Actions, game.catalogue.Locales()...)
+	case "accessibility":
+		gameActions.Actions = append(gameActions.Actions, "on", "off")
 	case "move":
-		for _, exit := range game.player.CurrentRoom.Exits {
-			gameActions.Actions = append(gameActions.Actions, exit.Name)
+		for _, direction := range sortedNames(player.CurrentRoom.Exits) {
+			if !player.CurrentRoom.Exits[direction].Hidden {
+				gameActions.Actions = append(gameActions.Actions, direction)
+			}
 		}
 	default:
-		return gameActions
+		return gameActions, nil
 	}
-	return gameActions
+	return gameActions, nil
 }
-func executeCommand(input PlayerInput, game *Game) string {
+
+func executeCommand(input PlayerInput, game *Game, player *Player) string {
 
 	command := input.Command
 
 	cmd, exists := Commands[command]
 
 	if command == game.computerPassword {
-		return game.unlockComputer.Outcome
+		return player.text("event."+game.unlockComputer.Description, game.unlockComputer.Outcome)
 	}
 
 	if !exists {
-		return fmt.Sprintf("Unknown command: %s", command)
+		return player.text("unknown", "Unknown command: %s", escapeMarkup(command))
 	}
-	return cmd.Execute(input, game)
+	return cmd.Execute(input, game, player)
+
+}
 
+type listener struct {
+	id     int
+	notify func(GameEvent)
 }
 
+// OnEvent registers a listener that is called with every GameEvent the game
+// produces, on the goroutine running the command. Listeners must not call
+// back into the game. The returned function removes the listener.
+func (game *Game) OnEvent(notify func(GameEvent)) func() {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.nextListenerID++
+	id := game.nextListenerID
+	game.listeners = append(game.listeners, listener{id: id, notify: notify})
+	return func() {
+		game.mu.Lock()
+		defer game.mu.Unlock()
+		for i, registered := range game.listeners {
+			if registered.id == id {
+				game.listeners = append(game.listeners[:i], game.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit must be called with the game locked.
+func (game *Game) emit(event GameEvent) {
+	event.Time = time.Now()
+	for _, registered := range game.listeners {
+		registered.notify(event)
+	}
+}
+
+// RunGame runs a command as the first player to have joined, for
+// single-player games.
 func (game *Game) RunGame(playerInput PlayerInput) GameResponse {
+	return game.RunGameAs(game.firstPlayerID(), playerInput)
+}
+
+// RunGameAs runs a command as the given player. Commands from different
+// players are applied one at a time, so two players reaching for the same
+// item cannot both take it.
+func (game *Game) RunGameAs(playerID string, playerInput PlayerInput) GameResponse {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return GameResponse{Message: fmt.Sprintf("Unknown player: %s", playerID), GameOver: true}
+	}
+	if player.exited {
+		return GameResponse{Message: player.text("goodbye", "Thank you for playing!"), GameOver: true}
+	}
+	game.expireIfDue()
+
+	received := GameEvent{Type: CommandReceived, Player: player.Name, Command: playerInput.Command, Args: playerInput.Args}
+	if playerInput.Command == "whisper" {
+		received.Recipients = []string{player.Name}
+	}
+	game.emit(received)
+
+	wasOver := game.gameOver
+	takesTime := game.takesTime(player, playerInput.Command)
+	response := game.runGame(player, playerInput)
+	if !wasOver && !game.gameOver && takesTime {
+		game.tick(player, playerInput.Command, &response)
+	}
+	if !wasOver && game.gameOver {
+		game.emit(GameEvent{Type: GameEnded, Player: player.Name, Room: player.CurrentRoom.Name, Message: PlainText(response.Message)})
+	}
+	if len(player.heard) > 0 {
+		response.Message = strings.Join(player.heard, "") + response.Message
+		player.heard = nil
+	}
+	response.Message = player.display().Show(response.Message)
+	response.RemainingSeconds = game.remainingSeconds()
+	return response
+}
+
+// DisableChat turns off say, shout and whisper, for worlds where each player
+// should solve the puzzle on their own.
+func (game *Game) DisableChat() {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.chatDisabled = true
+}
+
+// TellPlayer queues a message from the facilitator for the top of the
+// player's next response, shown and translated like the rest of it.
+func (game *Game) TellPlayer(playerID string, message string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return ErrUnknownPlayer
+	}
+	player.hear(player.text("facilitator.message", "Message from the facilitator: %s\n\n", escapeMarkup(message)))
+	return nil
+}
+
+// Listen passes everything the player hears from elsewhere, such as chat,
+// scheduled events and facilitator messages, to listener as it happens,
+// rendered for the player's display, instead of keeping it for the top of
+// their next response. Anything already waiting is passed on straight away.
+// The listener is called with the game locked and must not call back into
+// it. The returned function stops listening.
+func (game *Game) Listen(playerID string, listener func(message string, gameOver bool)) (func(), error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return nil, ErrUnknownPlayer
+	}
+	game.nextListenerID++
+	id := game.nextListenerID
+	player.listenerID = id
+	player.listener = func(message string) {
+		listener(player.display().Show(strings.TrimRight(message, "\n")), game.gameOver || player.exited)
+	}
+	for _, message := range player.heard {
+		player.listener(message)
+	}
+	player.heard = nil
+	return func() {
+		game.mu.Lock()
+		defer game.mu.Unlock()
+		if player.listenerID == id {
+			player.listener = nil
+		}
+	}, nil
+}
+
+// chat delivers text from one player to others, at the top of their next
+// response and as a ChatMessage event only they can see.
+func (game *Game) chat(from *Player, channel string, to []*Player, text string) {
+	english := map[string]string{"say": "%s says: %s\n\n", "shout": "%s shouts: %s\n\n", "whisper": "%s whispers: %s\n\n"}[channel]
+	var recipients []string
+	for _, player := range to {
+		player.hear(player.text("chat."+channel, english, escapeMarkup(from.Name), escapeMarkup(text)))
+		recipients = append(recipients, player.Name)
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	game.emit(GameEvent{Type: ChatMessage, Player: from.Name, Room: from.CurrentRoom.Name, Channel: channel, Message: text, Recipients: recipients})
+}
+
+// otherPlayers lists the players still in the world other than player,
+// optionally only those in the same room.
+func (game *Game) otherPlayers(player *Player, sameRoom bool) []*Player {
+	var others []*Player
+	for _, other := range game.players {
+		if other != player && !other.exited && (!sameRoom || other.CurrentRoom == player.CurrentRoom) {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+func (game *Game) runGame(player *Player, playerInput PlayerInput) GameResponse {
 	abandonedLanyard := game.staffRoom.Items["abandoned-lanyard"]
 	tea := game.staffRoom.Items["tea"]
 	lanyard := game.staffRoom.Items["lanyard"]
-	firstPlate := game.codingLab.Items["first-plate"]
-	secondPlate := game.codingLab.Items["second-plate"]
-	thirdPlate := game.codingLab.Items["third-plate"]
-	fourthPlate := game.codingLab.Items["fourth-plate"]
-	fifthPlate := game.codingLab.Items["fifth-plate"]
-	sixthPlate := game.codingLab.Items["sixth-plate"]
-
-	sofa := game.staffRoom.Entities["sofa"]
-	terminal := game.terminalRoom.Entities["terminal"]
-	computer := game.codingLab.Entities["computer"]
-	kettle := game.staffRoom.Entities["kettle"]
-	dishwasher := game.staffRoom.Entities["dishwasher"]
-	desk := game.codingLab.Entities["desk"]
-	alan := game.codingLab.Entities["alan"]
-	dan := game.terminalRoom.Entities["dan"]
-	rosie := game.staffRoom.Entities["rosie"]
+	stack := game.codingLab.Containers["stack"]
+
+	sofa := game.findEntity("sofa")
+	terminal := game.findEntity("terminal")
+	kettle := game.findEntity("kettle")
+	dishwasher := game.findEntity("dishwasher")
+	desk := game.findEntity("desk")
+	dan := game.findEntity("dan")
 
 	var response GameResponse
 	response.GameOver = false
 
-	if !global.GameOver {
-		if game.player.CurrentEntity != nil && game.player.CurrentEntity.Name == "sofa" && !sofaApproachedFirst {
+	if !game.gameOver {
+		if game.anyPlayerApproaching("sofa") && !game.sofaApproachedFirst {
 			abandonedLanyard.Hidden = false
 			sofa.SetDescription("Your fellow academy student continues to sleep on the sofa. Something tells you it's down to you to get stuff done today...")
-			sofaApproachedFirst = true
+			game.sofaApproachedFirst = true
 		}
 
-		if game.player.CurrentEntity != nil && game.player.CurrentEntity.Name == "kettle" && !kettleApproachedFirst {
+		if game.anyPlayerApproaching("kettle") && !game.kettleApproachedFirst {
 			tea.Hidden = false
 			kettle.SetDescription("A kettle — essential for survival, impossible to function without one nearby.")
-			kettleApproachedFirst = true
+			game.kettleApproachedFirst = true
+			game.scheduleIn(15, game.teaCools())
 		}
 
-		if game.player.CurrentEntity != nil && game.player.CurrentEntity.Name == "desk" && !deskApproachedFirst {
-			firstPlate.Hidden = false
-			secondPlate.Hidden = false
-			thirdPlate.Hidden = false
-			fourthPlate.Hidden = false
-			fifthPlate.Hidden = false
-			sixthPlate.Hidden = false
+		if game.anyPlayerApproaching("desk") && !game.deskApproachedFirst {
+			stack.Hidden = false
 			desk.SetDescription("Despite the disarray, it's clear this desk sees frequent use, with just enough space left to get work done.")
-			deskApproachedFirst = true
+			game.deskApproachedFirst = true
 		}
 
-		for _, validInteraction := range ValidInteractions {
-			if validInteraction.Event.Description == "get-your-lanyard" && validInteraction.Event.Triggered && !lanyardEventCompleted {
+		for _, validInteraction := range game.validInteractions {
+			if validInteraction.Event.Description == "get-your-lanyard" && validInteraction.Event.Triggered && !game.lanyardEventCompleted {
 				lanyard.Hidden = false
-				rosie.SetDescription("Can I help with anything else?")
-				lanyardEventCompleted = true
-			}
-		}
-
-		dishwasherLoaded := true
-
-		// Check if all plates have been loaded
-		plates := []*Interaction{
-			ValidInteractions[1],
-			ValidInteractions[2],
-			ValidInteractions[3],
-			ValidInteractions[4],
-			ValidInteractions[5],
-			ValidInteractions[6],
-		}
-
-		for _, plate := range plates {
-			if !plate.Event.Triggered {
-				dishwasherLoaded = false
-				break
+				game.lanyardEventCompleted = true
 			}
 		}
 
 		if !game.dishwasherChallengeWon.Triggered {
-			if dishwasherLoaded {
-				game.player.TriggerEvent(game.dishwasherChallengeWon)
-				alan.SetDescription("Ah, so you've managed to load the dishwasher! Splendid work — consider this challenge complete.\nI could have done it myself instead of writing that clever recursive function, but where's the fun in that?\nAfter all, they pay me for my intellect, not for doing the heavy lifting!\nBut I digress. You're free to proceed to the terminal room and speak with Dan for your final challenge.\nYou're doing an excellent job; keep it up!")
+			if dishwasher.Container.isFull() {
+				outcome := player.TriggerEvent(game.dishwasherChallengeWon)
 				dan.Hidden = false
 				terminal.Hidden = false
 
 				return GameResponse{
-					Message:  game.dishwasherChallengeWon.Outcome,
+					Message:  outcome,
 					GameOver: false,
 				}
 			}
 		}
 
-		if _, ok := game.player.Inventory["abandoned-lanyard"]; ok {
-			global.GameOver = true
+		if game.anyPlayerLost() {
+			game.gameOver = true
 			response.GameOver = true
 			return response
 		}
 
-		if global.GameOver {
-			response.Message = "Thank you for playing!"
+		if game.gameOver {
+			response.Message = player.text("goodbye", "Thank you for playing!")
 			return response
 		}
 
 		if playerInput.Command == "start" {
-			if !game.introductionShown {
-				response.Message = game.introduction
-				game.introductionShown = true
+			if !player.introductionShown {
+				response.Message = player.text("introduction", game.introduction)
+				player.introductionShown = true
 				return response
 			}
 		}
@@ -206,80 +434,249 @@ func (game *Game) RunGame(playerInput PlayerInput) GameResponse {
 		input := playerInput.Command
 
 		if input == "exit" {
-			response.Message = "Thank you for playing!"
+			response.Message = player.text("goodbye", "Thank you for playing!")
 			response.GameOver = true
-			global.GameOver = true
+			player.exited = true
+			player.Leave()
+			if game.allPlayersExited() {
+				game.gameOver = true
+			}
 			return response
 		}
 
-		if game.isAttemptingPassword {
+		if player.isAttemptingPassword {
 			if game.remainingPasswordAttempts == 1 && input != game.computerPassword {
-				response.Message = "Alan's computer is locked. Thank you for playing!"
+				response.Message = player.text("password.lost", "Alan's computer is locked. Thank you for playing!")
 				response.GameOver = true
-				global.GameOver = true
+				game.gameOver = true
 				return response
 			}
 			if input == game.computerPassword {
-				game.player.TriggerEvent(game.unlockComputer)
-				computer.SetDescription("function completeTask(pile)\n   if pile == 0:\n      return 'Task Complete'\n   else:\n      completeTask(pile - 1)\n")
-				alan.SetDescription("You've cracked the password! Impressive work...")
-				game.isAttemptingPassword = false
+				player.TriggerEvent(game.unlockComputer)
+				player.isAttemptingPassword = false
 				desk.Hidden = false
 				dishwasher.Hidden = false
 			} else if input == "leave" {
-				game.isAttemptingPassword = false
+				player.isAttemptingPassword = false
 			} else {
 				game.remainingPasswordAttempts--
-				response.Message = fmt.Sprintf("Incorrect password. Remaining attempts: %d", game.remainingPasswordAttempts)
-				computer.SetDescription(fmt.Sprintf("Alan's computer. Remaining attempts: %d.\nEnter the password:", game.remainingPasswordAttempts))
+				response.Message = player.text("password.wrong", "Incorrect password. Remaining attempts: %d", game.remainingPasswordAttempts)
 				return response
 			}
 		}
 
-		if game.isAttemptingTerminal {
+		if player.isAttemptingTerminal {
 			if input == "leave" {
-				game.isAttemptingTerminal = false
-				game.player.Leave()
+				player.isAttemptingTerminal = false
+				player.Leave()
 				return response
 			}
 
-			if !game.IsFirstCommand {
+			if !player.secretFilesOpened {
 				if input == "cd /secret-files" {
-					response.Message = "The terminal displays:\n\n/secret-files/\n\nEnter the final command to win the game!"
-					game.IsFirstCommand = true
+					response.Message = player.text("terminal.opened", "The terminal displays:\n\n/secret-files/\n\nEnter the final command to win the game!")
+					player.secretFilesOpened = true
 					terminal.SetDescription("A sleek terminal sits on the desk...")
 				} else {
-					response.Message = fmt.Sprintf("bash: %s: command not found", input)
+					response.Message = player.text("terminal.unknown", "bash: %s: command not found", escapeMarkup(input))
 				}
 				return response
 			} else {
 				if input == "cat unlock-exits-instructions.txt" {
-					response.Message = "Victory Achieved! The doors swing wide."
+					response.Message = player.text("terminal.won", "Victory Achieved! The doors swing wide.")
 					response.GameOver = true
-					global.GameOver = true
+					game.gameOver = true
 					return response
 				} else {
-					response.Message = fmt.Sprintf("bash: %s: command not found", input)
+					response.Message = player.text("terminal.unknown", "bash: %s: command not found", escapeMarkup(input))
 					return response
 				}
 			}
 		}
 
-		result := executeCommand(playerInput, game)
+		result := executeCommand(playerInput, game, player)
+		if game.anyPlayerLost() {
+			game.gameOver = true
+		}
 		response.Message = result
-		response.GameOver = global.GameOver
+		response.GameOver = game.gameOver
 		return response
 	}
+	response.Message = player.text("goodbye", "Thank you for playing!")
+	response.GameOver = true
 	return response
 }
 
+// anyPlayerApproaching reports whether some player is standing at the named
+// entity.
+func (game *Game) anyPlayerApproaching(entityName string) bool {
+	for _, player := range game.players {
+		if player.CurrentEntity != nil && player.CurrentEntity.Name == entityName {
+			return true
+		}
+	}
+	return false
+}
+
+// anyPlayerLost reports whether some player has done something that loses
+// the game for everybody.
+func (game *Game) anyPlayerLost() bool {
+	for _, player := range game.players {
+		if player.caught || player.brokePlates {
+			return true
+		}
+	}
+	return false
+}
+
+func (game *Game) allPlayersExited() bool {
+	for _, player := range game.players {
+		if !player.exited {
+			return false
+		}
+	}
+	return true
+}
+
+// Progress summarises how far a player has got, for facilitators watching.
+type Progress struct {
+	PlayerName                string
+	Room                      string
+	TriggeredEvents           []string
+	RemainingPasswordAttempts int
+	GameOver                  bool
+}
+
+// Progress lists the triggered events in the order the puzzle expects them.
+// Events are shared, so every player in a world sees the same list.
+func (game *Game) Progress(playerID string) (Progress, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return Progress{}, ErrUnknownPlayer
+	}
+	progress := Progress{
+		PlayerName:                player.Name,
+		Room:                      player.CurrentRoom.Name,
+		TriggeredEvents:           []string{},
+		RemainingPasswordAttempts: game.remainingPasswordAttempts,
+		GameOver:                  game.gameOver || player.exited,
+	}
+	events := []*Event{game.validInteractions[0].Event, game.unlockComputer}
+	for _, interaction := range game.validInteractions[1:] {
+		events = append(events, interaction.Event)
+	}
+	events = append(events, game.dishwasherChallengeWon)
+	for _, event := range events {
+		if event.Triggered {
+			progress.TriggeredEvents = append(progress.TriggeredEvents, event.Description)
+		}
+	}
+	return progress, nil
+}
+
+// IsOver reports whether the game has been won or lost, or every player has
+// exited.
+func (game *Game) IsOver() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	return game.gameOver
+}
+
+// IsOverFor reports whether the game is over for the given player, either
+// because the world's game is over or because they exited.
+func (game *Game) IsOverFor(playerID string) bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	return game.gameOver || player == nil || player.exited
+}
+
+// Join adds a player to the world in the break room and returns their ID.
+// An empty name is replaced by one made up from the order they joined in.
+func (game *Game) Join(name string) (string, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.gameOver {
+		return "", ErrGameOver
+	}
+	game.joined++
+	id := fmt.Sprintf("player-%d", game.joined)
+	if name == "" {
+		name = id
+	}
+	for _, player := range game.players {
+		if player.Name == name {
+			return "", ErrNameTaken
+		}
+	}
+
+	player := &Player{
+		ID:              id,
+		Name:            name,
+		CurrentRoom:     game.staffRoom,
+		Inventory:       make(map[string]*Item),
+		AvailableWeight: 20,
+		Capacity:        game.capacity,
+		catalogue:       game.catalogue,
+		CurrentEntity:   nil,
+		Interactions:    game.validInteractions,
+		events:          game.emit,
+	}
+	player.narrator = func() Narrative { return game.narrative(player) }
+	game.players = append(game.players, player)
+	return id, nil
+}
+
+// Leave takes a player out of the world as if they had exited.
+func (game *Game) Leave(playerID string) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	if player == nil || player.exited {
+		return
+	}
+	player.exited = true
+	player.Leave()
+	if game.allPlayersExited() {
+		game.gameOver = true
+	}
+}
+
+func (game *Game) findPlayer(playerID string) *Player {
+	for _, player := range game.players {
+		if player.ID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+func (game *Game) firstPlayerID() string {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	if len(game.players) == 0 {
+		return ""
+	}
+	return game.players[0].ID
+}
+
+// SetupGame sets up the world for a single player.
 func (game *Game) SetupGame() {
+	game.SetupWorld()
+	game.Join("")
+}
 
-	game.introduction = "It's the last day at the Academy, and you and your fellow graduates are ready to take on the final hack-day challenge.\nHowever, this time, it's different. Alan and Dan, your instructors, have prepared something more intense than ever before — a true test of your problem-solving and coding skills.\nThe doors to the academy are locked, the windows sealed. The only way out is to find and solve a series of riddles that lead to the terminal in a hidden room.\nThe challenge? Crack the code on the terminal to unlock the doors. But it's not that simple.\nYou'll need to gather items, approach Alan and Dan for cryptic tips, and outsmart the obstacles they've laid out for you.\nAs the tension rises, only your wits, teamwork, and knowledge can guide you to freedom.\nAre you ready to escape?\nOh and remember... You don't want to make Rosie grumpy! So don't do anything crazy.\n\nif at any point you feel lost, type 'commands' to display the list of all commands.\nThe command 'look' is always useful to get your bearings and see the options available to you.\nThe command 'exit' will make you quit the game at any time. Make sure you do mean to use it, or you will inadvertently lose all of your progress!"
+// SetupWorld sets up the rooms, items and puzzle shared by every player who
+// joins the world.
+func (game *Game) SetupWorld() {
 
-	game.introductionShown = false
+	game.introduction = "It's the last day at the Academy, and you and your fellow graduates are ready to take on the final hack-day challenge.\nHowever, this time, it's different. Alan and Dan, your instructors, have prepared something more intense than ever before — a true test of your problem-solving and coding skills.\nThe doors to the academy are locked, the windows sealed. The only way out is to find and solve a series of riddles that lead to the terminal in a hidden room.\nThe challenge? Crack the code on the terminal to unlock the doors. But it's not that simple.\nYou'll need to gather items, approach Alan and Dan for cryptic tips, and outsmart the obstacles they've laid out for you.\nAs the tension rises, only your wits, teamwork, and knowledge can guide you to freedom.\nAre you ready to escape?\nOh and remember... You don't want to make Rosie grumpy! So don't do anything crazy.\n\nif at any point you feel lost, type 'commands' to display the list of all commands.\nThe command 'look' is always useful to get your bearings and see the options available to you.\nThe command 'exit' will make you quit the game at any time. Make sure you do mean to use it, or you will inadvertently lose all of your progress!"
 
-	ValidInteractions = []*Interaction{
+	game.validInteractions = []*Interaction{
 		{
 			ItemName:   "tea",
 			EntityName: "rosie",
@@ -323,6 +720,16 @@ func (game *Game) SetupGame() {
 
 	game.computerPassword = "iiwsccrtc"
 
+	game.clock = Clock{Minutes: dayStartsAt}
+
+	game.commandCosts = make(map[string]int)
+	for command, cost := range defaultCommandCosts {
+		game.commandCosts[command] = cost
+	}
+
+	game.scheduleWorldEvents()
+	game.catalogue = DefaultCatalogue()
+
 	game.remainingPasswordAttempts = 10
 
 	game.staffRoom = &Room{
@@ -330,7 +737,8 @@ func (game *Game) SetupGame() {
 		Description: "A cozy lounge designed for both academy students and tutors, offering a welcoming space to unwind and socialise.\nComfortable seating invites you to relax, while the warm ambiance encourages lively conversations and friendly exchanges.",
 		Items:       make(map[string]*Item),
 		Entities:    make(map[string]*Entity),
-		Exits:       make(map[string]*Room),
+		Exits:       make(map[string]*Exit),
+		Containers:  make(map[string]*Container),
 	}
 
 	game.codingLab = &Room{
@@ -338,7 +746,8 @@ func (game *Game) SetupGame() {
 		Description: "A bright, tech-filled room with sleek workstations, whiteboards, and collaborative spaces.\nThe air buzzes with creativity as students code, share ideas, and tackle challenges together.",
 		Items:       make(map[string]*Item),
 		Entities:    make(map[string]*Entity),
-		Exits:       make(map[string]*Room),
+		Exits:       make(map[string]*Exit),
+		Containers:  make(map[string]*Container),
 	}
 
 	game.terminalRoom = &Room{
@@ -346,48 +755,75 @@ func (game *Game) SetupGame() {
 		Description: "As you step into the terminal room, you're greeted by the soft hum of machines and the flickering glow of monitors lining the walls.\n\nThe air is charged with a sense of urgency, filled with the scent of freshly brewed coffee mingling with the faint odor of electrical components.\n\nIn the center of the room, a sleek, state-of-the-art terminal stands atop a polished wooden desk.",
 		Items:       make(map[string]*Item),
 		Entities:    make(map[string]*Entity),
-		Exits:       make(map[string]*Room),
-	}
-
-	game.staffRoom.Exits["south"] = game.codingLab
-	game.codingLab.Exits["north"] = game.staffRoom
-	game.codingLab.Exits["east"] = game.terminalRoom
-	game.terminalRoom.Exits["west"] = game.codingLab
-
-	game.staffRoom.Items["tea"] = &Item{Name: "tea", Description: "A steaming cup of Yorkshire tea, rich and comforting.", Weight: 2, Hidden: true}
-	game.staffRoom.Items["lanyard"] = &Item{Name: "lanyard", Description: "Your lanyard, a key to unlocking any door within the building.", Weight: 1, Hidden: true}
-	game.staffRoom.Items["abandoned-lanyard"] = &Item{Name: "abandoned-lanyard", Description: "An abandoned lanyard, a key to unlocking any door within the building.", Weight: 1, Hidden: true}
-	game.codingLab.Items["first-plate"] = &Item{Name: "first-plate", Description: "The plate on top of the stack.", Weight: 6, Hidden: true}
-	game.codingLab.Items["second-plate"] = &Item{Name: "second-plate", Description: "The second plate of the stack.", Weight: 6, Hidden: true}
-	game.codingLab.Items["third-plate"] = &Item{Name: "third-plate", Description: "The third plate of the stack.", Weight: 6, Hidden: true}
-	game.codingLab.Items["fourth-plate"] = &Item{Name: "fourth-plate", Description: "The fourth plate of the stack.", Weight: 6, Hidden: true}
-	game.codingLab.Items["fifth-plate"] = &Item{Name: "fifth-plate", Description: "The fifth plate of the stack.", Weight: 6, Hidden: true}
-	game.codingLab.Items["sixth-plate"] = &Item{Name: "sixth-plate", Description: "The plate at the bottom of the stack.", Weight: 6, Hidden: true}
+		Exits:       make(map[string]*Exit),
+		Containers:  make(map[string]*Container),
+	}
+
+	lanyardDoor := "Doors are shut for you if you don't have a lanyard."
+	connect(game.staffRoom, "south", game.codingLab, "north", &Lock{Item: "lanyard", Keycard: true, Locked: true}, lanyardDoor)
+	connect(game.codingLab, "east", game.terminalRoom, "west", &Lock{Item: "lanyard", Keycard: true, Locked: true}, lanyardDoor)
+
+	game.staffRoom.Items["tea"] = &Item{
+		Name:        "tea",
+		Description: "{{if ge .Item.temperature 60}}A steaming cup of Yorkshire tea, rich and comforting.{{else if ge .Item.temperature 40}}A cup of Yorkshire tea, going cold. Rosie won't thank you for dawdling.{{else}}A cup of Yorkshire tea, stone cold.{{end}}",
+		Weight:      2,
+		Hidden:      true,
+		Properties:  Properties{"temperature": 90},
+		Changes:     []Change{{Property: "temperature", By: -3, Until: 20}},
+	}
+	game.staffRoom.Items["lanyard"] = &Item{Name: "lanyard", Description: "Your lanyard, a key to unlocking any door within the building.", Weight: 1, Hidden: true, Wearable: true}
+	game.staffRoom.Items["abandoned-lanyard"] = &Item{Name: "abandoned-lanyard", Description: "An abandoned lanyard, a key to unlocking any door within the building.", Weight: 1, Hidden: true, Wearable: true}
 	game.codingLab.Items["cd"] = &Item{Name: "cd", Description: "A compact disc with '\\secret-files' written on it in bold letters.\nIt almost seems to call out to you, hinting at hidden knowledge.", Weight: 1, Hidden: false}
 
-	game.staffRoom.Entities["rosie"] = &Entity{Name: "rosie", Description: "Ugh, what? Sorry, I can't think straight without a brew. Get me some tea, and then we'll talk...", Hidden: false}
+	plates := []string{"first-plate", "second-plate", "third-plate", "fourth-plate", "fifth-plate", "sixth-plate"}
+	loadThem := "You can't just leave those plates lying around! It's time to load them into the dishwasher!"
+	game.codingLab.Containers["stack"] = &Container{
+		Name:        "stack",
+		Description: "A wobbly stack of greasy plates, best taken from the top.",
+		Order:       Stack,
+		Accepts:     plates,
+		Hidden:      true,
+		Fragile:     true,
+		Spill:       "As you attempt to grab the greasy plates without removing the ones stacked above them, they slip from your grasp and shatter, creating a chaotic mess.\n\nNow Rosie is very grumpy.\n",
+		Items: []*Item{
+			{Name: "sixth-plate", Description: "The plate at the bottom of the stack.", Weight: 6, DropRefusal: loadThem},
+			{Name: "fifth-plate", Description: "The fifth plate of the stack.", Weight: 6, DropRefusal: loadThem},
+			{Name: "fourth-plate", Description: "The fourth plate of the stack.", Weight: 6, DropRefusal: loadThem},
+			{Name: "third-plate", Description: "The third plate of the stack.", Weight: 6, DropRefusal: loadThem},
+			{Name: "second-plate", Description: "The second plate of the stack.", Weight: 6, DropRefusal: loadThem},
+			{Name: "first-plate", Description: "The plate on top of the stack.", Weight: 6, DropRefusal: loadThem},
+		},
+	}
+
+	game.staffRoom.Entities["rosie"] = &Entity{Name: "rosie", Description: `{{if happened "get-your-lanyard"}}Can I help with anything else?{{else if ge .Turn 30}}Still no tea? I've been sat here for ages. Kettle's right there, you know...{{else}}Ugh, what? Sorry, I can't think straight without a brew. Get me some tea, and then we'll talk...{{end}}`, Hidden: false}
 	game.staffRoom.Entities["kettle"] = &Entity{Name: "kettle", Description: "You set the kettle to boil, brewing the strongest cup of tea you've ever made. A comforting aroma fills the room as the tea is now ready.\n\n(tea can now be found in the room)\n", Hidden: false}
-	game.staffRoom.Entities["sofa"] = &Entity{Name: "sofa", Description: "You come across one of your fellow academy students fast asleep on the sofa. Next to them, their lanyard lies carelessly within reach.\nYou know you shouldn't take it, but the temptation lingers...\n\n(abandoned-lanyard can now be found in the room)\n", Hidden: false}
+	game.staffRoom.Entities["sofa"] = &Entity{Name: "sofa", Description: "You come across one of your fellow academy students fast asleep on the sofa. Next to them, their lanyard lies carelessly within reach.\nYou know you shouldn't take it, but the temptation lingers...\n\n(abandoned-lanyard can now be found in the room)\n", Hidden: false, Reactions: []*Reaction{
+		{ItemName: "abandoned-lanyard", Accept: true, Response: "You slip the lanyard back beside your sleeping classmate. Nobody needs to know.\n"},
+	}}
 	game.staffRoom.Entities["dishwasher"] = &Entity{Name: "dishwasher", Description: "A stainless steel dishwasher sits quietly in the corner, its door slightly ajar.\nThe faint scent of soap lingers, and the racks inside are half-empty, waiting for the next load of dirty dishes to be placed inside.\nIt hums faintly, as if anticipating the task it was built for.", Hidden: true}
+	game.staffRoom.Entities["dishwasher"].Container = &Container{Name: "dishwasher", Capacity: len(plates), Accepts: plates}
 	game.staffRoom.Entities["cat"] = &Entity{Name: "cat", Description: "On one of the chairs, a fluffy cat lounges lazily, wearing a collar with a name tag that reads 'unlock-exits-instructions.txt'\n\nAn odd name for a cat. You get the feeling that this feline is more than it seems, possibly guarding crucial information", Hidden: false}
-	game.codingLab.Entities["computer"] = &Entity{Name: "computer", Description: "Alan's computer. You need the password to get in.\n\nRemaining attempts: 10.\n\nType 'leave' to stop entering the password.\n\nEnter the password:\n", Hidden: false}
-	game.codingLab.Entities["alan"] = &Entity{Name: "alan", Description: "Oh, you've finally made it... What are you waiting for, crack on with the code. The computer is right there...\nWhat's that? You don't know the password? Hmm... I seem to have forgotten it myself, but I do recall it's nine letters long.\nAnd for the love of all that's good, it's definitely not 'waterfall'!", Hidden: false}
+	game.codingLab.Entities["computer"] = &Entity{Name: "computer", Description: "{{if happened \"computer-is-unlocked\"}}{code}function completeTask(pile)\n   if pile == 0:\n      return 'Task Complete'\n   else:\n      completeTask(pile - 1)\n{/code}{{else}}Alan's computer. You need the password to get in.\n\nRemaining attempts: {{.Attempts}}.\n\nType 'leave' to stop entering the password.\n\nEnter the password:\n{{end}}", Hidden: false}
+	game.codingLab.Entities["alan"] = &Entity{Name: "alan", Description: "{{if happened \"dishwasher-loaded\"}}Ah, so you've managed to load the dishwasher! Splendid work — consider this challenge complete.\nI could have done it myself instead of writing that clever recursive function, but where's the fun in that?\nAfter all, they pay me for my intellect, not for doing the heavy lifting!\nBut I digress. You're free to proceed to the terminal room and speak with Dan for your final challenge.\nYou're doing an excellent job; keep it up!{{else if happened \"computer-is-unlocked\"}}You've cracked the password! Impressive work...{{else}}Oh, you've finally made it... What are you waiting for, crack on with the code. The computer is right there...\nWhat's that? You don't know the password? Hmm... I seem to have forgotten it myself, but I do recall it's nine letters long.\nAnd for the love of all that's good, it's definitely not 'waterfall'!{{end}}", Hidden: false, Reactions: []*Reaction{
+		{ItemName: "tea", Accept: false, Response: "Tea? That's kind of you, but I'd take it to Rosie. Nobody gets anything out of Rosie before the first brew of the day.\n"},
+	}}
 	game.codingLab.Entities["agile-manifesto"] = &Entity{Name: "agile-manifesto", Description: "A large, framed document hangs prominently on the wall, its edges slightly frayed\nYou can almost feel the energy of past brainstorming sessions in the air as you read the four key values:\n\nIndividuals and Interactions over processes and tools.\n\nWorking Software over comprehensive documentation.\n\nCustomer Collaboration over contract negotiation.\n\nResponding To Change over following a plan.\n", Hidden: false}
 	game.codingLab.Entities["desk"] = &Entity{Name: "desk", Description: "You approach the desk and spot a messy pile of dirty plates, stacked haphazardly. You think to yourself that somebody was too lazy to load the dishwasher.\nThe stack is too heavy to carry all the plates at once, and taking plates from the centre or bottom of the stack could pose a risk...\n\n(stack of plates can now be found in the room)\n\n", Hidden: true}
 	game.terminalRoom.Entities["terminal"] = &Entity{Name: "terminal", Description: "A sleek terminal sits on the desk, its screen displaying lines of code and system commands.\nThe keyboard, slightly worn, hints at frequent use.\nThis device is essential for executing tasks and accessing the building's network.\n\nEnter your commands below or type 'leave' to exit the terminal.\n\n", Hidden: true}
 	game.terminalRoom.Entities["dan"] = &Entity{Name: "dan", Description: "Congratulations on making it this far! I must say, I'm genuinely impressed. It appears I'm your final boss — muahahaha!\n...Oh, pardon my theatrics. Now, listen closely: the terminal holds the secret instructions to escape the building.\nYou only need two commands to access them.\nLook around the building to find some clues...\nYes, I know, this is actually the easiest task so far. If I am being totally honest, we just want to be done by 4pm...\nWhat are you standing there for? Get to it!\n", Hidden: true}
 
-	game.isAttemptingPassword = false
-
-	game.isAttemptingTerminal = false
-
-	game.IsFirstCommand = false
-
-	game.player = &Player{
-		CurrentRoom:     game.staffRoom,
-		Inventory:       make(map[string]*Item),
-		AvailableWeight: 20,
-		CurrentEntity:   nil,
+	game.staffRoom.Entities["rosie"].Detail = &Detail{
+		First:  "Rosie is slumped in a chair, eyes half closed, clutching an empty mug.\n",
+		Layers: []Layer{{Event: "get-your-lanyard", Text: "Rosie sips the tea, looking far more awake and keeping an eye on everybody.\n"}},
+	}
+	game.staffRoom.Entities["cat"].Detail = &Detail{
+		First: "The cat's name tag reads 'unlock-exits-instructions.txt'. The cat stares back at you, unimpressed.\n",
+		Again: "The cat yawns. Still unimpressed.\n",
 	}
+	game.codingLab.Items["cd"].Detail = &Detail{
+		First: "You turn the cd over. On the back, in smaller letters: 'Everything starts with a cd'.\n",
+	}
+
+	game.setUpNPCs()
 
 }
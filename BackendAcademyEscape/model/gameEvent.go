@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type GameEventType string
+
+const (
+	CommandReceived GameEventType = "command_received"
+	RoomChanged     GameEventType = "room_changed"
+	ItemTaken       GameEventType = "item_taken"
+	EventTriggered  GameEventType = "event_triggered"
+	GameEnded       GameEventType = "game_ended"
+	ChatMessage     GameEventType = "chat_message"
+	ItemGiven       GameEventType = "item_given"
+	TimedEvent      GameEventType = "timed_event"
+	NPCMoved        GameEventType = "npc_moved"
+	ExitUnlocked    GameEventType = "exit_unlocked"
+	ExitLocked      GameEventType = "exit_locked"
+	ItemStored      GameEventType = "item_stored"
+	ItemsCombined   GameEventType = "items_combined"
+)
+
+// GameEvent records something that happened in the game, for observers that
+// want to follow play without parsing the text returned by RunGame.
+type GameEvent struct {
+	Type    GameEventType `json:"type"`
+	Time    time.Time     `json:"time"`
+	Player  string        `json:"player,omitempty"`
+	Command string        `json:"command,omitempty"`
+	Args    []string      `json:"args,omitempty"`
+	Room    string        `json:"room,omitempty"`
+	Item    string        `json:"item,omitempty"`
+	Target  string        `json:"target,omitempty"`
+	Event   string        `json:"event,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Channel string        `json:"channel,omitempty"`
+	// Recipients limits who may see the event, by player name. Events
+	// without recipients are for everybody in the world.
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// VisibleTo reports whether the named player may see the event.
+func (event GameEvent) VisibleTo(playerName string) bool {
+	if len(event.Recipients) == 0 {
+		return true
+	}
+	for _, recipient := range event.Recipients {
+		if recipient == playerName {
+			return true
+		}
+	}
+	return false
+}
@@ -4,4 +4,25 @@ type Interaction struct {
 	ItemName   string
 	EntityName string
 	Event      *Event
+	// Requires are conditions on the item's properties, and Refusal is
+	// shown when they don't hold.
+	Requires []Condition
+	Refusal  string
+}
+
+// allows reports whether item is in a state the interaction accepts.
+func (i *Interaction) allows(item *Item) bool {
+	for _, condition := range i.Requires {
+		if !condition.holds(item.Properties) {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *Interaction) refusal(p *Player) string {
+	if i.Refusal != "" {
+		return p.text("interaction."+i.EntityName+"."+i.ItemName+".refused", i.Refusal)
+	}
+	return p.text("use.refused", "%s won't take %s like that.\n", i.EntityName, i.ItemName)
 }
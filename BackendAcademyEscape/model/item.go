@@ -4,13 +4,42 @@ type Item struct {
 	Name        string
 	Description string
 	Weight      int
+	Volume      int
 	Hidden      bool
+	// Wearable items can be equipped, which frees up a hand.
+	Wearable bool
+	// DropRefusal is shown instead of dropping the item, for items that
+	// have to be put somewhere in particular.
+	DropRefusal string
+	// Container makes the item something that holds other items, like a bag.
+	Container *Container
+	Detail    *Detail
+	// Properties are the item's state, and Changes how it changes by itself
+	// as time passes.
+	Properties Properties
+	Changes    []Change
+}
+
+// totalWeight is the item's weight with everything inside it.
+func (i *Item) totalWeight() int {
+	if i.Container == nil {
+		return i.Weight
+	}
+	return i.Weight + i.Container.weight()
 }
 
 func (i *Item) SetDescription(description string) {
 	i.Description = description
 }
 
-func (i *Item) GetDescription() string {
-	return i.Description
+// AddItem puts an item in the room with the given name.
+func (game *Game) AddItem(room string, item *Item) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	in := game.roomNamed(room)
+	if in == nil {
+		return ErrUnknownRoom
+	}
+	in.Items[item.Name] = item
+	return nil
 }
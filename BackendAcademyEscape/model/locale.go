@@ -0,0 +1,187 @@
+package model
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language the game is written in.
+const DefaultLocale = "en"
+
+var ErrUnknownLocale = errors.New("no translations for that language")
+
+// Catalogue holds translations of player-facing text, by locale and then by
+// message ID, like "fr" -> "take.missing" -> "Vous ne pouvez pas prendre %s\n".
+// The English text sits in the code next to each ID, and is what players
+// see whenever a translation is missing.
+//
+// World content is found by the name of the thing: "room.<name>",
+// "entity.<name>", "item.<name>", "container.<name>" and
+// "event.<description>" for descriptions and outcomes,
+// "exit.<room>.<direction>" and "exit.<room>.<direction>.locked" for going
+// through a door, and "examine.<name>", "examine.<name>.again" and
+// "examine.<name>.<event>" for what examining it shows. What characters
+// say is "npc.<entity>.guard", "npc.<entity>.follow",
+// "npc.<entity>.greet" and, on being given something,
+// "reaction.<entity>.<item>". Refusals are "item.<name>.drop" and
+// "interaction.<entity>.<item>.refused", and the outcomes of recipes and
+// scheduled events are "recipe.<inputs>" and "scheduled.<name>".
+//
+// Every other ID starts with the command or feature it belongs to, like
+// "take.missing" or "capacity.carrying", and never with one of the
+// prefixes above. Messages that would otherwise share a prefix with world
+// content, like "error.item.missing" or "ui.room.header", live under "ui."
+// and "error." instead, so that they can't clash with a thing's name.
+type Catalogue map[string]map[string]string
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// DefaultCatalogue loads the translations that come with the game.
+func DefaultCatalogue() Catalogue {
+	catalogue := make(Catalogue)
+	files, _ := locales.ReadDir("locales")
+	for _, file := range files {
+		data, err := locales.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			continue
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			continue
+		}
+		catalogue[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return catalogue
+}
+
+// translate finds a message in the locale, falling back from a regional
+// locale like "pt-BR" to its language, "pt".
+func (c Catalogue) translate(locale string, id string) (string, bool) {
+	for locale != "" {
+		if text, ok := c[locale][id]; ok {
+			return text, true
+		}
+		cut := strings.LastIndexAny(locale, "-_")
+		if cut < 0 {
+			break
+		}
+		locale = locale[:cut]
+	}
+	return "", false
+}
+
+// Locales lists the languages there are translations for, and English.
+func (c Catalogue) Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range c {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// supported returns the locale to use for a language tag, or "" if there
+// are no translations for it or its language.
+func (c Catalogue) supported(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for tag != "" {
+		if tag == DefaultLocale {
+			return tag
+		}
+		for locale := range c {
+			if strings.ToLower(locale) == tag {
+				return locale
+			}
+		}
+		cut := strings.LastIndexAny(tag, "-_")
+		if cut < 0 {
+			break
+		}
+		tag = tag[:cut]
+	}
+	return ""
+}
+
+// Match picks the best supported locale from an Accept-Language header,
+// like "fr-CH, fr;q=0.9, en;q=0.8", or DefaultLocale if none are.
+func (c Catalogue) Match(acceptLanguage string) string {
+	best, bestWeight := DefaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if locale := c.supported(tag); locale != "" && weight > bestWeight {
+			best, bestWeight = locale, weight
+		}
+	}
+	return best
+}
+
+// SetCatalogue replaces the translations used in this world.
+func (game *Game) SetCatalogue(catalogue Catalogue) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.catalogue = catalogue
+	for _, player := range game.players {
+		player.catalogue = catalogue
+	}
+}
+
+// MatchLocale picks the player's language from an Accept-Language header.
+func (game *Game) MatchLocale(acceptLanguage string) string {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	return game.catalogue.Match(acceptLanguage)
+}
+
+// SetLocale changes the language a player is shown the game in.
+func (game *Game) SetLocale(playerID string, locale string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return ErrUnknownPlayer
+	}
+	supported := game.catalogue.supported(locale)
+	if supported == "" {
+		return ErrUnknownLocale
+	}
+	player.Locale = supported
+	return nil
+}
+
+// locale is the player's language, DefaultLocale unless they chose one.
+func (p *Player) locale() string {
+	if p.Locale == "" {
+		return DefaultLocale
+	}
+	return p.Locale
+}
+
+// text is a message in the player's language, formatted with args. english
+// is shown when there is no translation.
+func (p *Player) text(id string, english string, args ...any) string {
+	text := english
+	if translated, ok := p.catalogue.translate(p.Locale, id); ok {
+		text = translated
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
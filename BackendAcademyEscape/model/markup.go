@@ -0,0 +1,170 @@
+package model
+
+import "strings"
+
+// Responses are written with a small markup language so that each Display
+// can show them its own way. Tags come in pairs and can be nested:
+//
+//	{h}You are in break-room{/h}
+//	{li}{entity}rosie{/entity}{/li}
+//	{code}function completeTask(pile){/code}
+//
+// {h} is a heading, {li} an entry in a list, {code} a code listing and {em}
+// emphasis. {item}, {entity}, {player} and {exit} mark names the player
+// can do something with. Anything in braces that isn't a tag is left as
+// it is, and {lbrace} is a literal "{" for text typed by players.
+
+// Node is a piece of a response: some text, or a tag around more nodes.
+type Node struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Children []Node `json:"children,omitempty"`
+}
+
+// Node types. List only appears once consecutive list items are grouped.
+const (
+	TextNode     = "text"
+	HeadingNode  = "heading"
+	ListNode     = "list"
+	ListItemNode = "listItem"
+	CodeNode     = "code"
+	EmphasisNode = "emphasis"
+	ItemNode     = "item"
+	EntityNode   = "entity"
+	PlayerNode   = "player"
+	ExitNode     = "exit"
+)
+
+var markupTags = map[string]string{
+	"h":      HeadingNode,
+	"li":     ListItemNode,
+	"code":   CodeNode,
+	"em":     EmphasisNode,
+	"item":   ItemNode,
+	"entity": EntityNode,
+	"player": PlayerNode,
+	"exit":   ExitNode,
+}
+
+// escapeMarkup stops text typed by players being read as tags.
+func escapeMarkup(text string) string {
+	return strings.ReplaceAll(text, "{", "{lbrace}")
+}
+
+// ParseMarkup reads marked up text into nodes. Tags left open are closed
+// at the end, and stray closing tags are ignored.
+func ParseMarkup(text string) []Node {
+	root := &Node{}
+	stack := []*Node{root}
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() > 0 {
+			top := stack[len(stack)-1]
+			top.Children = append(top.Children, Node{Type: TextNode, Text: pending.String()})
+			pending.Reset()
+		}
+	}
+
+	for len(text) > 0 {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			pending.WriteString(text)
+			break
+		}
+		pending.WriteString(text[:open])
+		text = text[open:]
+		end := strings.IndexByte(text, '}')
+		if end < 0 {
+			pending.WriteString(text)
+			break
+		}
+		tag := text[1:end]
+		name, closing := strings.CutPrefix(tag, "/")
+		nodeType, known := markupTags[name]
+		switch {
+		case tag == "lbrace":
+			pending.WriteByte('{')
+		case !known:
+			pending.WriteString(text[:end+1])
+		case closing:
+			flush()
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Type == nodeType {
+					stack = closeNodes(stack, i)
+					break
+				}
+			}
+		default:
+			flush()
+			stack = append(stack, &Node{Type: nodeType})
+		}
+		text = text[end+1:]
+	}
+	flush()
+	stack = closeNodes(stack, 1)
+	return root.Children
+}
+
+// closeNodes closes the nodes on the stack from index from upwards, adding
+// each to its parent.
+func closeNodes(stack []*Node, from int) []*Node {
+	for len(stack) > from {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, *node)
+	}
+	return stack
+}
+
+// PlainText shows marked up text without any markup, with "- " in front of
+// list entries.
+func PlainText(text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	var plain strings.Builder
+	writePlain(&plain, ParseMarkup(text))
+	return plain.String()
+}
+
+func writePlain(out *strings.Builder, nodes []Node) {
+	for _, node := range nodes {
+		switch node.Type {
+		case TextNode:
+			out.WriteString(node.Text)
+		case ListItemNode:
+			out.WriteString("- ")
+			writePlain(out, node.Children)
+		default:
+			writePlain(out, node.Children)
+		}
+	}
+}
+
+// groupLists puts runs of list items, with nothing but line breaks between
+// them, into list nodes.
+func groupLists(nodes []Node) []Node {
+	var grouped []Node
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		node.Children = groupLists(node.Children)
+		if node.Type != ListItemNode {
+			grouped = append(grouped, node)
+			continue
+		}
+		list := Node{Type: ListNode, Children: []Node{node}}
+		for j := i + 1; j < len(nodes); j++ {
+			if nodes[j].Type == ListItemNode {
+				next := nodes[j]
+				next.Children = groupLists(next.Children)
+				list.Children = append(list.Children, next)
+				i = j
+			} else if nodes[j].Type != TextNode || strings.TrimSpace(nodes[j].Text) != "" {
+				break
+			}
+		}
+		grouped = append(grouped, list)
+	}
+	return grouped
+}
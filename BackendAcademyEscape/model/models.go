@@ -5,9 +5,12 @@ type Describable interface {
 	GetDescription() string
 }
 
+// GameResponse is what a player is shown after a command. RemainingSeconds
+// is only set in worlds with a time limit.
 type GameResponse struct {
-	Message  string `json:"message"`
-	GameOver bool   `json:"game_over"`
+	Message          string `json:"message"`
+	GameOver         bool   `json:"game_over"`
+	RemainingSeconds *int   `json:"remaining_seconds,omitempty"`
 }
 
 type GameActions struct {
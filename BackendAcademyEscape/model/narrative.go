@@ -0,0 +1,99 @@
+package model
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Narrative is the game state that descriptions, event outcomes and other
+// story text can refer to. Text containing "{{" is a text/template over a
+// Narrative, so one description can change with the game instead of being
+// replaced, like
+//
+//	{{if happened "computer-is-unlocked"}}Welcome back, {{.Player}}.{{else}}Locked.{{end}}
+//
+// Besides the fields, templates can call has "item", which is true while
+// the player carries the item, and happened "event", which is true once the
+// puzzle event has been triggered.
+type Narrative struct {
+	Player    string
+	Room      string
+	Attempts  int
+	Turn      int
+	Time      string
+	Inventory []string
+	// Item is the properties of the item being described, so that an item
+	// can say "{{if lt .Item.temperature 40}}Cold{{else}}Hot{{end}} tea."
+	Item     Properties
+	happened func(event string) bool
+}
+
+// narrative is the state the player's text is rendered with.
+func (game *Game) narrative(player *Player) Narrative {
+	narrative := player.ownNarrative()
+	narrative.Attempts = game.remainingPasswordAttempts
+	narrative.Turn = game.clock.Turn
+	narrative.Time = game.clock.String()
+	narrative.happened = game.eventTriggered
+	return narrative
+}
+
+// ownNarrative is the part of the narrative a player has without a game,
+// for players built by hand.
+func (p *Player) ownNarrative() Narrative {
+	narrative := Narrative{Player: p.Name, Inventory: sortedNames(p.Inventory)}
+	if p.CurrentRoom != nil {
+		narrative.Room = p.CurrentRoom.Name
+	}
+	return narrative
+}
+
+// render executes text as a template over the narrative.
+func (n Narrative) render(text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("narrative").Option("missingkey=zero").Funcs(template.FuncMap{
+		"has": func(item string) bool {
+			return slices.Contains(n.Inventory, item)
+		},
+		"happened": func(event string) bool {
+			return n.happened != nil && n.happened(event)
+		},
+	}).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, n); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// narrate renders text with the player's game state.
+func (p *Player) narrate(text string) string {
+	return p.narrateItem(text, nil)
+}
+
+// narrateItem renders text with the player's game state and, if item isn't
+// nil, the item's properties. Text that fails to render is logged and left
+// out, so that callers fall back to a plain description rather than showing
+// the template.
+func (p *Player) narrateItem(text string, item *Item) string {
+	narrative := p.ownNarrative()
+	if p.narrator != nil {
+		narrative = p.narrator()
+	}
+	if item != nil {
+		narrative.Item = item.Properties
+	}
+	rendered, err := narrative.render(text)
+	if err != nil {
+		log.Printf("rendering %q: %v", text, err)
+		return ""
+	}
+	return rendered
+}
@@ -1,150 +1,189 @@
 package model
 
 import (
-	"academy-adventure-game/global"
 	"fmt"
 	"strings"
 )
 
 type Player struct {
+	ID              string
+	Name            string
 	CurrentRoom     *Room
 	Inventory       map[string]*Item
 	CurrentEntity   *Entity
 	CarriedWeight   int
 	AvailableWeight int
+	// Capacity decides what fits in the inventory. Nil means WeightLimit.
+	Capacity             Encumbrance
+	Equipped             map[string]bool
+	Interactions         []*Interaction
+	brokePlates          bool
+	caught               bool
+	exited               bool
+	introductionShown    bool
+	isAttemptingPassword bool
+	isAttemptingTerminal bool
+	secretFilesOpened    bool
+	heard                []string
+	// listener, when set, is told what the player hears as it happens
+	// instead of it waiting in heard.
+	listener   func(string)
+	listenerID int
+	examined   map[*Detail]bool
+	events     func(GameEvent)
+	narrator   func() Narrative
+	// Locale is the language the player is shown the game in.
+	Locale string
+	// Display renders the player's responses. Nil means PlainDisplay.
+	Display Display
+	// Descriptions is Verbose, Brief or "" for how often the room is
+	// described.
+	Descriptions string
+	visited      map[*Room]bool
+	catalogue    Catalogue
 }
 
-var plateOrder = []string{"first-plate", "second-plate", "third-plate", "fourth-plate", "fifth-plate", "sixth-plate"}
-var currentPlateIndex = 0
+// hear queues a message from somebody else for the top of the player's
+// next response, or passes it straight on to their listener.
+func (p *Player) hear(message string) {
+	if p.listener != nil {
+		p.listener(message)
+		return
+	}
+	p.heard = append(p.heard, message)
+}
+
+// ValidInteractions is used by players that were not given their own
+// Interactions, such as players built by hand outside of SetupGame.
 var ValidInteractions = []*Interaction{}
 
 func (p *Player) Move(direction string, display Display) string {
 	if p.CurrentEntity != nil {
 		p.CurrentEntity = nil
 	}
-	if newRoom, ok := p.CurrentRoom.Exits[direction]; ok {
-		p.CurrentRoom = newRoom
-
-		return display.Show(fmt.Sprintf("You are in %s\n", p.CurrentRoom.Name))
-	} else {
-		return display.Show("You can't go that way!\n")
-	}
-}
-
-func isPlate(itemName string) bool {
-	for _, plate := range plateOrder {
-		if itemName == plate {
-			return true
+	if exit, ok := p.visibleExit(direction); ok {
+		if !exit.opensFor(p) {
+			return show(display, exit.lockedMessage(p, direction))
 		}
+		from := p.CurrentRoom.Name
+		p.visit(p.CurrentRoom)
+		p.CurrentRoom = exit.To
+		p.emit(GameEvent{Type: RoomChanged, Room: exit.To.Name})
+
+		arrived := p.arrival()
+		p.visit(p.CurrentRoom)
+		return show(display, p.text("exit."+from+"."+direction, exit.UnlockedDescription)+arrived)
+	} else {
+		return show(display, p.text("move.blocked", "You can't go that way!\n"))
 	}
-	return false
 }
 
 func (p *Player) Take(itemName string, display Display) string {
 	item, ok := p.CurrentRoom.Items[itemName]
 	switch {
 	case !ok || item.Hidden:
-		return display.Show(fmt.Sprintf("You can't take %s\n", itemName))
-
-	case p.AvailableWeight < item.Weight:
-		return display.Show(fmt.Sprintln("Weight limit reached! Please drop an item before taking more."))
-
-	case isPlate(itemName):
-		if itemName == plateOrder[currentPlateIndex] {
-			currentPlateIndex++
-			return p.AddToInventory(item, display)
-
-		} else {
-			global.GameOver = true
-			return display.Show(fmt.Sprintln("As you attempt to grab the greasy plates without removing the ones stacked above them, they slip from your grasp and shatter, creating a chaotic mess.\n\nNow Rosie is very grumpy."))
+		if container := p.containerHolding(itemName); container != "" {
+			return p.TakeFrom(itemName, container, display)
 		}
+		return show(display, p.text("take.missing", "You can't take %s\n", escapeMarkup(itemName)))
 
 	default:
+		if err := p.canCarry(item); err != nil {
+			return show(display, p.capacityMessage(err))
+		}
 		return p.AddToInventory(item, display)
 	}
 }
 
 func (p *Player) AddToInventory(item *Item, display Display) string {
-	p.Inventory[item.Name] = item
-	p.ChangeCarriedWeight(item, "increase")
+	p.carry(item)
 	delete(p.CurrentRoom.Items, item.Name)
-	if item.Name == "abandoned-lanyard" {
-		return "Rosie caught you in the act of swiping a lanyard from a fellow student.\nYou have made Rosie grumpy and you've lost the game."
-	}
-	return display.Show(fmt.Sprintf("%s has been added to your inventory.\n", item.Name))
-}
-
-func (p *Player) ChangeCarriedWeight(item *Item, operation string) {
-	switch {
-	case operation == "increase":
-		p.CarriedWeight += item.Weight
-		p.AvailableWeight -= item.Weight
-		return
-	case operation == "decrease":
-		p.CarriedWeight -= item.Weight
-		p.AvailableWeight += item.Weight
-		return
-	}
+	p.emit(GameEvent{Type: ItemTaken, Item: item.Name, Room: p.CurrentRoom.Name})
+	return show(display, p.text("take.done", "%s has been added to your inventory.\n", item.Name))
 }
 
 func (p *Player) Drop(itemName string, display Display) string {
 	if item, ok := p.Inventory[itemName]; ok {
-		if isPlate(itemName) {
-			return display.Show("You can't just leave those plates lying around! It's time to load them into the dishwasher!")
+		if item.DropRefusal != "" {
+			return show(display, p.text("item."+itemName+".drop", item.DropRefusal))
 		}
 
-		delete(p.Inventory, item.Name)
-		p.ChangeCarriedWeight(item, "decrease")
+		p.release(item)
 		p.CurrentRoom.Items[item.Name] = item
 
-		return display.Show(fmt.Sprintf("You dropped %s.\n\n", item.Name))
+		return show(display, p.text("drop.done", "You dropped %s.\n\n", item.Name))
 	} else {
-		return display.Show(fmt.Sprintf("You don't have %s.\n\n", itemName))
+		return show(display, p.text("drop.missing", "You don't have %s.\n\n", escapeMarkup(itemName)))
 	}
 }
 
 func (p *Player) ShowInventory(display Display) string {
 	if len(p.Inventory) == 0 {
-		return display.Show(fmt.Sprintf("Your inventory is empty.\nAvailable space: %d\n", p.AvailableWeight))
+		return show(display, p.text("inventory.empty", "Your inventory is empty.\n")+p.encumbrance().Summary(p))
 	}
 	var itemArray []string
-	itemArray = append(itemArray, (fmt.Sprintf("Available space: %d\nYour inventory contains:\n", p.AvailableWeight)))
-	for itemName, item := range p.Inventory {
-		itemArray = append(itemArray, (fmt.Sprintf("- %s: %s Weight: %d\n", itemName, item.Description, item.Weight)))
+	itemArray = append(itemArray, p.encumbrance().Summary(p)+p.text("inventory.contents", "{h}Your inventory contains:{/h}\n"))
+	for _, itemName := range sortedNames(p.Inventory) {
+		item := p.Inventory[itemName]
+		worn := ""
+		if p.Equipped[itemName] {
+			worn = p.text("inventory.worn", " (worn)")
+		}
+		itemArray = append(itemArray, (p.text("inventory.item", "{li}{item}%s{/item}%s: %s Weight: %d{/li}\n", itemName, worn, p.describeItem(item), item.Weight)))
+		if item.Container != nil {
+			itemArray = append(itemArray, (p.text("inventory.inside", "  inside: %s\n", item.Container.contents(p))))
+		}
 	}
-	return display.Show(strings.Join(itemArray, ""))
+	return show(display, strings.Join(itemArray, ""))
 }
 
 func (p *Player) ShowRoom(display Display) string {
 	var returnValue []string
-	returnValue = append(returnValue, display.Show(fmt.Sprintf("You are in %s\n\n%s\n", p.CurrentRoom.Name, p.CurrentRoom.Description)))
+	if p.describesRoom() {
+		returnValue = append(returnValue, show(display, p.text("ui.room.header", "{h}You are in %s{/h}\n\n%s\n", p.CurrentRoom.Name, p.narrate(p.text("room."+p.CurrentRoom.Name, p.CurrentRoom.Description)))))
+	} else {
+		returnValue = append(returnValue, show(display, p.text("ui.room.name", "{h}You are in %s{/h}\n", p.CurrentRoom.Name)))
+	}
 
 	if p.EntitiesArePresent() {
-		returnValue = append(returnValue, display.Show("\nYou can approach:\n"))
-		for _, entity := range p.CurrentRoom.Entities {
+		returnValue = append(returnValue, show(display, p.text("ui.room.entities", "\n{h}You can approach:{/h}\n")))
+		for _, name := range sortedNames(p.CurrentRoom.Entities) {
+			entity := p.CurrentRoom.Entities[name]
 			switch {
 			case p.PlayerIsEngaged():
 				if entity.Name == p.CurrentEntity.Name {
-					returnValue = append(returnValue, display.Show(fmt.Sprintf("- %s (currently approached)\n", entity.Name)))
+					returnValue = append(returnValue, show(display, p.text("ui.room.approached", "{li}{entity}%s{/entity} (currently approached){/li}\n", entity.Name)))
 				} else if !entity.Hidden {
-					returnValue = append(returnValue, display.Show(fmt.Sprintf("- %s\n", entity.Name)))
+					returnValue = append(returnValue, show(display, fmt.Sprintf("{li}{entity}%s{/entity}{/li}\n", entity.Name)))
 				}
 			default:
 				if !entity.Hidden {
-					returnValue = append(returnValue, display.Show(fmt.Sprintf("- %s\n", entity.Name)))
+					returnValue = append(returnValue, show(display, fmt.Sprintf("{li}{entity}%s{/entity}{/li}\n", entity.Name)))
 				}
 			}
 		}
 	}
 
 	if p.ItemsArePresent() {
-		returnValue = append(returnValue, display.Show("\nThe room contains:"))
-		for itemName, item := range p.CurrentRoom.Items {
-			if !item.Hidden {
-				returnValue = append(returnValue, display.Show(fmt.Sprintf("\n- %s: %s Weight: %d\n", itemName, item.Description, item.Weight)))
+		returnValue = append(returnValue, show(display, p.text("ui.room.items", "\n{h}The room contains:{/h}")))
+		for _, itemName := range sortedNames(p.CurrentRoom.Items) {
+			if item := p.CurrentRoom.Items[itemName]; !item.Hidden {
+				returnValue = append(returnValue, show(display, p.text("ui.room.item", "\n{li}{item}%s{/item}: %s Weight: %d{/li}\n", itemName, p.describeItem(item), item.Weight)))
+			}
+		}
+	}
+
+	if p.ContainersArePresent() {
+		returnValue = append(returnValue, show(display, p.text("ui.room.containers", "\n{h}You can look inside:{/h}\n")))
+		for _, name := range sortedNames(p.CurrentRoom.Containers) {
+			if container := p.CurrentRoom.Containers[name]; !container.Hidden {
+				returnValue = append(returnValue, show(display, fmt.Sprintf("{li}{item}%s{/item}: %s (%s){/li}\n", name, p.text("container."+name, container.Description), container.contents(p))))
 			}
 		}
 	}
+	if p.accessible() {
+		returnValue = append(returnValue, show(display, "\n"+p.exitsSentence()))
+	}
 	return strings.Join(returnValue, "")
 }
 
@@ -163,6 +202,15 @@ func (p *Player) ItemsArePresent() bool {
 	return false
 }
 
+func (p *Player) ContainersArePresent() bool {
+	for _, container := range p.CurrentRoom.Containers {
+		if !container.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Player) EntitiesArePresent() bool {
 	if len(p.CurrentRoom.Entities) != 0 {
 		for _, entity := range p.CurrentRoom.Entities {
@@ -181,9 +229,9 @@ func (p *Player) Approach(entityName string, display Display) string {
 	if entity, ok := p.CurrentRoom.Entities[entityName]; ok && !entity.Hidden {
 
 		p.CurrentEntity = entity
-		return display.Show(entity.Description)
+		return show(display, p.narrate(p.text("entity."+entity.Name, entity.Description)))
 	} else {
-		return display.Show(fmt.Sprintf("You can't approach %s.\n", entityName))
+		return show(display, p.text("approach.missing", "You can't approach %s.\n", escapeMarkup(entityName)))
 	}
 }
 
@@ -192,41 +240,124 @@ func (p *Player) Leave() string {
 		p.CurrentEntity = nil
 		return p.ShowRoom(ConsoleDisplay{})
 	} else {
-		return ("You have not approached anything. If you wish to leave the game, use the exit command.")
+		return p.text("leave.nothing", "You have not approached anything. If you wish to leave the game, use the exit command.")
 	}
 }
 
 func (p *Player) ShowMap(display Display) string {
 	var returnValue []string
-	for direction, exit := range p.CurrentRoom.Exits {
-		returnValue = append(returnValue, (fmt.Sprintf("%s: %s\n", direction, exit.Name)))
+	for _, direction := range sortedNames(p.CurrentRoom.Exits) {
+		exit := p.CurrentRoom.Exits[direction]
+		if exit.Hidden {
+			continue
+		}
+		if !exit.opensFor(p) {
+			returnValue = append(returnValue, (p.text("map.locked", "{exit}%s{/exit}: %s (locked)\n", direction, exit.To.Name)))
+			continue
+		}
+		returnValue = append(returnValue, (fmt.Sprintf("{exit}%s{/exit}: %s\n", direction, exit.To.Name)))
 	}
-	return display.Show(strings.Join(returnValue, ""))
+	return show(display, strings.Join(returnValue, ""))
 }
 
 func (p *Player) Use(itemName string, target string, display Display) string {
 
 	if p.CurrentEntity == nil {
-		return display.Show("Approach to use an item.\n")
+		return show(display, p.text("use.approach", "Approach to use an item.\n"))
 
 	}
 
 	if p.CurrentEntity.Name != target {
-		return display.Show(fmt.Sprintf("%s not found.\n", target))
+		return show(display, p.text("use.target", "%s not found.\n", escapeMarkup(target)))
 	}
 
 	if itemIsNotInInventory(p, itemName) {
-		return display.Show(fmt.Sprintf("You don't have %s.\n", itemName))
+		return show(display, p.text("error.item.missing", "You don't have %s.\n", escapeMarkup(itemName)))
 
 	}
 
-	for _, interaction := range ValidInteractions {
+	if p.CurrentEntity.Container != nil {
+		return p.PutIn(itemName, target, display)
+	}
+
+	for _, interaction := range p.interactions() {
 		if interactionIsValid(interaction, itemName, target) {
+			if !interaction.allows(p.Inventory[itemName]) {
+				return show(display, interaction.refusal(p))
+			}
 
 			return handleInteraction(p, interaction, itemName)
 		}
 	}
-	return display.Show(fmt.Sprintf("You can't use %s on %s.\n", itemName, target))
+	return show(display, p.text("use.invalid", "You can't use %s on %s.\n", escapeMarkup(itemName), escapeMarkup(target)))
+}
+
+// GiveToPlayer hands an item to another player, if they can carry it.
+func (p *Player) GiveToPlayer(itemName string, recipient *Player, display Display) string {
+	item, ok := p.Inventory[itemName]
+	if !ok {
+		return show(display, p.text("error.item.missing", "You don't have %s.\n", escapeMarkup(itemName)))
+	}
+	if err := recipient.canCarry(item); err != nil {
+		return show(display, p.text("give.full", "%s can't carry %s.\n", escapeMarkup(recipient.Name), itemName))
+	}
+
+	p.release(item)
+	recipient.carry(item)
+	recipient.hear(recipient.text("give.received", "%s gave you %s.\n\n", escapeMarkup(p.Name), itemName))
+	p.emit(GameEvent{Type: ItemGiven, Item: itemName, Room: p.CurrentRoom.Name, Target: recipient.Name})
+	return show(display, p.text("give.done", "You gave %s to %s.\n", itemName, escapeMarkup(recipient.Name)))
+}
+
+// GiveToEntity offers an item to an entity in the room. An item the puzzle
+// expects the entity to have triggers its event as if it had been used on
+// it; otherwise the entity's reactions decide whether it keeps the item.
+func (p *Player) GiveToEntity(itemName string, entity *Entity, display Display) string {
+	item, ok := p.Inventory[itemName]
+	if !ok {
+		return show(display, p.text("error.item.missing", "You don't have %s.\n", escapeMarkup(itemName)))
+	}
+	if entity.Container != nil {
+		return p.PutIn(itemName, entity.Name, display)
+	}
+
+	for _, interaction := range p.interactions() {
+		if interactionIsValid(interaction, itemName, entity.Name) {
+			if !interaction.allows(item) {
+				return show(display, interaction.refusal(p))
+			}
+			p.release(item)
+			entity.receive(item)
+			p.emit(GameEvent{Type: ItemGiven, Item: itemName, Room: p.CurrentRoom.Name, Target: entity.Name})
+			return p.TriggerEvent(interaction.Event)
+		}
+	}
+
+	reaction := entity.reactionTo(itemName)
+	if reaction == nil {
+		return show(display, p.text("give.unwanted", "%s doesn't want %s.\n", entity.Name, escapeMarkup(itemName)))
+	}
+	if !reaction.Accept {
+		return show(display, reaction.response(p, entity.Name))
+	}
+
+	p.release(item)
+	entity.receive(item)
+	p.emit(GameEvent{Type: ItemGiven, Item: itemName, Room: p.CurrentRoom.Name, Target: entity.Name})
+
+	if returned, ok := entity.Inventory[reaction.HandsBack]; ok && p.canCarry(returned) == nil {
+		delete(entity.Inventory, returned.Name)
+		p.carry(returned)
+		p.emit(GameEvent{Type: ItemTaken, Item: returned.Name, Room: p.CurrentRoom.Name})
+	}
+	return show(display, reaction.response(p, entity.Name))
+}
+
+func (p *Player) interactions() []*Interaction {
+	if p.Interactions == nil {
+		return ValidInteractions
+	}
+	return p.Interactions
 }
 
 func itemIsNotInInventory(player *Player, itemName string) bool {
@@ -238,14 +369,23 @@ func interactionIsValid(interaction *Interaction, itemName string, target string
 }
 
 func handleInteraction(player *Player, interaction *Interaction, itemName string) string {
-	
-	player.ChangeCarriedWeight(player.Inventory[itemName], "decrease")
-	delete(player.Inventory, itemName)
+
+	if item := player.Inventory[itemName]; item.wearOut() {
+		player.release(item)
+	}
 	return player.TriggerEvent(interaction.Event)
 }
 
 func (p *Player) TriggerEvent(event *Event) string {
-	
+
 	event.Triggered = true
-	return event.Outcome
+	p.emit(GameEvent{Type: EventTriggered, Event: event.Description})
+	return p.narrate(p.text("event."+event.Description, event.Outcome))
+}
+
+func (p *Player) emit(event GameEvent) {
+	event.Player = p.Name
+	if p.events != nil {
+		p.events(event)
+	}
 }
@@ -2,7 +2,7 @@ package model
 
 type PlayerInput struct {
 	Command string   `json:"command"`
-	Args    []string `json:"args"`
+	Args    []string `json:"args,omitempty"`
 }
 
 func (p *PlayerInput) ParseInput() {
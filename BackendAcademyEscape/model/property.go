@@ -0,0 +1,184 @@
+package model
+
+import (
+	"log"
+	"maps"
+	"slices"
+)
+
+// Properties are an item's state, like its temperature or how many uses it
+// has left. Values are ints, bools or strings.
+type Properties map[string]any
+
+// Int returns a number property, or 0 if the item doesn't have it.
+func (p Properties) Int(name string) int {
+	value, _ := p[name].(int)
+	return value
+}
+
+// Bool returns a true or false property, or false if the item doesn't have it.
+func (p Properties) Bool(name string) bool {
+	value, _ := p[name].(bool)
+	return value
+}
+
+// Text returns a string property, or "" if the item doesn't have it.
+func (p Properties) Text(name string) string {
+	value, _ := p[name].(string)
+	return value
+}
+
+// Change moves a number property by By on every turn while the item can be
+// seen, stopping at Until. Tea going cold is a Change to its temperature.
+type Change struct {
+	Property string
+	By       int
+	Until    int
+}
+
+func (c Change) apply(properties Properties) {
+	value, ok := properties[c.Property].(int)
+	if !ok {
+		return
+	}
+	value += c.By
+	if (c.By < 0 && value < c.Until) || (c.By > 0 && value > c.Until) {
+		value = c.Until
+	}
+	properties[c.Property] = value
+}
+
+// Condition is something an item's property must satisfy, such as the tea
+// being at least 40 degrees. Op is one of =, !=, <, <=, > or >=, and only =
+// and != work for bools and strings.
+type Condition struct {
+	Property string
+	Op       string
+	Value    any
+}
+
+func (c Condition) holds(properties Properties) bool {
+	value, ok := properties[c.Property]
+	if !ok {
+		return false
+	}
+	if number, ok := value.(int); ok {
+		want, ok := c.Value.(int)
+		if !ok {
+			return false
+		}
+		switch c.Op {
+		case "<":
+			return number < want
+		case "<=":
+			return number <= want
+		case ">":
+			return number > want
+		case ">=":
+			return number >= want
+		}
+	}
+	switch c.Op {
+	case "=":
+		return value == c.Value
+	case "!=":
+		return value != c.Value
+	}
+	return false
+}
+
+// SetProperty changes one of the item's properties.
+func (i *Item) SetProperty(name string, value any) {
+	if i.Properties == nil {
+		i.Properties = make(Properties)
+	}
+	i.Properties[name] = value
+}
+
+// GetDescription shows the item's description. Descriptions are story
+// text, rendered with the item's properties as .Item; see Narrative.
+func (i *Item) GetDescription() string {
+	description, err := Narrative{Item: i.Properties}.render(i.Description)
+	if err != nil {
+		log.Printf("rendering %q: %v", i.Description, err)
+	}
+	return description
+}
+
+// describeItem is the item's description in the player's language,
+// rendered with the player's game state and the item's properties.
+func (p *Player) describeItem(item *Item) string {
+	return p.narrateItem(p.text("item."+item.Name, item.Description), item)
+}
+
+// copyItem makes a new item from a template, such as a recipe's result, so
+// that every copy has its own properties and, if it holds things, its own
+// container with copies of what's inside.
+func copyItem(item *Item) *Item {
+	copied := *item
+	copied.Properties = maps.Clone(item.Properties)
+	if item.Container != nil {
+		container := *item.Container
+		container.Accepts = slices.Clone(item.Container.Accepts)
+		container.Items = nil
+		for _, inside := range item.Container.Items {
+			container.Items = append(container.Items, copyItem(inside))
+		}
+		copied.Container = &container
+	}
+	return &copied
+}
+
+// lastUse reports whether using the item once more would use it up.
+func (i *Item) lastUse() bool {
+	uses, ok := i.Properties["uses"].(int)
+	return !ok || uses <= 1
+}
+
+// wearOut uses an item once. Items with a "uses" property are only used up
+// by their last use; reports whether the item is used up.
+func (i *Item) wearOut() bool {
+	if i.lastUse() {
+		return true
+	}
+	i.Properties["uses"] = i.Properties.Int("uses") - 1
+	return false
+}
+
+// ageItems applies the Changes of every item in play, wherever it is.
+// Hidden items, and items in hidden containers, haven't turned up yet and
+// don't change. Items in a closed container still do.
+func (game *Game) ageItems() {
+	var items []*Item
+	for _, room := range game.rooms() {
+		for _, item := range room.Items {
+			items = append(items, item)
+		}
+		for _, container := range room.Containers {
+			if !container.Hidden {
+				items = append(items, container.Items...)
+			}
+		}
+		for _, entity := range room.Entities {
+			if entity.Container != nil && !entity.Hidden {
+				items = append(items, entity.Container.Items...)
+			}
+		}
+	}
+	for _, player := range game.players {
+		for _, item := range player.Inventory {
+			items = append(items, item)
+			if item.Container != nil {
+				items = append(items, item.Container.Items...)
+			}
+		}
+	}
+	for _, item := range items {
+		if item.Hidden {
+			continue
+		}
+		for _, change := range item.Changes {
+			change.apply(item.Properties)
+		}
+	}
+}
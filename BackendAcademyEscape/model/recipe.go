@@ -0,0 +1,147 @@
+package model
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Recipe turns items into a new one. The result of one recipe can be an
+// input to another, for puzzles that take several steps.
+type Recipe struct {
+	// Inputs are the items needed, carried or lying in the room, in any
+	// order.
+	Inputs []string
+	// Keeps lists the inputs that aren't used up, like a tool.
+	Keeps []string
+	// Result is copied into the inventory, or left in the room if it is too
+	// heavy to carry. It can be nil for recipes that only trigger an event.
+	Result  *Item
+	Outcome string
+	Event   *Event
+}
+
+func (r *Recipe) matches(names []string) bool {
+	if len(names) != len(r.Inputs) {
+		return false
+	}
+	want := append([]string(nil), r.Inputs...)
+	got := append([]string(nil), names...)
+	sort.Strings(want)
+	sort.Strings(got)
+	for i := range want {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// id is the catalogue ID of the recipe's outcome, "recipe.<inputs>" with
+// the inputs in alphabetical order and joined by "+".
+func (r *Recipe) id() string {
+	inputs := append([]string(nil), r.Inputs...)
+	sort.Strings(inputs)
+	return "recipe." + strings.Join(inputs, "+")
+}
+
+func (r *Recipe) keeps(name string) bool {
+	for _, kept := range r.Keeps {
+		if kept == name {
+			return true
+		}
+	}
+	return false
+}
+
+// usesUp reports whether combining names uses up item. Inputs are taken from
+// the inventory first, so an item in the room is only used up if the player
+// doesn't also carry one with its name.
+func (r *Recipe) usesUp(player *Player, names []string, item *Item) bool {
+	if !slices.Contains(names, item.Name) || r.keeps(item.Name) || !item.lastUse() {
+		return false
+	}
+	if held, ok := player.Inventory[item.Name]; ok {
+		return held == item
+	}
+	return true
+}
+
+// AddRecipe adds a way of combining items to the world.
+func (game *Game) AddRecipe(recipe *Recipe) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	game.recipes = append(game.recipes, recipe)
+}
+
+// combine makes something out of the named items, if a recipe calls for
+// exactly those.
+func (game *Game) combine(player *Player, names []string) string {
+	for _, name := range names {
+		if _, ok := player.Inventory[name]; ok {
+			continue
+		}
+		if item, ok := player.CurrentRoom.Items[name]; ok && !item.Hidden {
+			continue
+		}
+		return player.text("error.item.missing", "You don't have %s.\n", escapeMarkup(name))
+	}
+
+	var recipe *Recipe
+	for _, candidate := range game.recipes {
+		if candidate.matches(names) {
+			recipe = candidate
+			break
+		}
+	}
+	if recipe == nil {
+		return player.text("combine.none", "You can't combine %s.\n", strings.Join(names, player.text("combine.with", " with ")))
+	}
+
+	// Items are keyed by name, so the result can't share one with an item
+	// that is still around once the inputs are used up.
+	if recipe.Result != nil {
+		name := recipe.Result.Name
+		if held, ok := player.Inventory[name]; ok && !recipe.usesUp(player, names, held) {
+			return player.text("combine.held", "You already have %s, so you can't make another.\n", name)
+		}
+		if lying, ok := player.CurrentRoom.Items[name]; ok && !recipe.usesUp(player, names, lying) {
+			return player.text("combine.here", "There is already %s here, so you can't make another.\n", name)
+		}
+	}
+
+	for _, name := range names {
+		if recipe.keeps(name) {
+			continue
+		}
+		if item, ok := player.Inventory[name]; ok {
+			if item.wearOut() {
+				player.release(item)
+			}
+		} else if player.CurrentRoom.Items[name].wearOut() {
+			delete(player.CurrentRoom.Items, name)
+		}
+	}
+
+	message := player.narrate(player.text(recipe.id(), recipe.Outcome))
+	if recipe.Result != nil {
+		result := copyItem(recipe.Result)
+		if message == "" {
+			message = player.text("combine.made", "You combine %s to make %s.\n", strings.Join(names, player.text("combine.with", " with ")), result.Name)
+		}
+		if player.canCarry(result) == nil {
+			player.carry(result)
+		} else {
+			player.CurrentRoom.Items[result.Name] = result
+			message += player.text("combine.heavy", "%s is too heavy to carry, so you put it down.\n", result.Name)
+		}
+		player.emit(GameEvent{Type: ItemsCombined, Item: result.Name, Args: names, Room: player.CurrentRoom.Name})
+	}
+	if message == "" {
+		message = player.text("combine.done", "You combine %s.\n", strings.Join(names, player.text("combine.with", " with ")))
+	}
+	if recipe.Event != nil && !recipe.Event.Triggered {
+		message += player.TriggerEvent(recipe.Event)
+	}
+	return message
+}
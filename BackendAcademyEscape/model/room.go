@@ -1,11 +1,17 @@
 package model
 
+import "slices"
+
 type Room struct {
 	Name        string
 	Description string
-	Exits       map[string]*Room
+	Exits       map[string]*Exit
 	Items       map[string]*Item
 	Entities    map[string]*Entity
+	Containers  map[string]*Container
+	// Features are parts of the room that can be examined but not taken or
+	// approached, like a noticeboard.
+	Features map[string]*Detail
 }
 
 func (r *Room) SetDescription(description string) {
@@ -15,3 +21,14 @@ func (r *Room) SetDescription(description string) {
 func (r *Room) GetDescription() string {
 	return r.Description
 }
+
+// sortedNames lists the names of the things in a room or an inventory in
+// alphabetical order, so that listings come out the same every time.
+func sortedNames[V any](things map[string]V) []string {
+	names := make([]string, 0, len(things))
+	for name := range things {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
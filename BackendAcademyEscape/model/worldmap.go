@@ -0,0 +1,322 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// MapLayout is the part of the world a player has explored, laid out on a
+// grid: the rooms they have been in, the rooms they have seen a way into,
+// and the exits between them. Rooms keep their place as more is explored.
+type MapLayout struct {
+	Width  int       `json:"width"`
+	Height int       `json:"height"`
+	Rooms  []MapRoom `json:"rooms"`
+	Exits  []MapExit `json:"exits"`
+}
+
+// MapRoom is a room on the grid. Rooms the player hasn't been in yet have
+// no name.
+type MapRoom struct {
+	Name    string `json:"name,omitempty"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Visited bool   `json:"visited"`
+	Current bool   `json:"current"`
+}
+
+// MapExit is a way out of a visited room. From and To are indexes into
+// Rooms.
+type MapExit struct {
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+	Locked    bool   `json:"locked"`
+}
+
+type point struct{ x, y int }
+
+var directionOffsets = map[string]point{
+	"north":     {0, -1},
+	"south":     {0, 1},
+	"east":      {1, 0},
+	"west":      {-1, 0},
+	"northeast": {1, -1},
+	"northwest": {-1, -1},
+	"southeast": {1, 1},
+	"southwest": {-1, 1},
+}
+
+// layOut places every room reachable from origin on a grid, each a step
+// from its neighbour in the direction of the exit between them. Exits that
+// are always there are followed first, so that revealing a hidden one
+// doesn't move rooms that were already drawn.
+func layOut(origin *Room) map[*Room]point {
+	positions := map[*Room]point{origin: {}}
+	taken := map[point]bool{{}: true}
+	order := []*Room{origin}
+	for _, followRevealed := range []bool{false, true} {
+		for i := 0; i < len(order); i++ {
+			room := order[i]
+			for _, direction := range sortedNames(room.Exits) {
+				exit := room.Exits[direction]
+				if _, placed := positions[exit.To]; placed || (exit.RevealedBy != "" && !followRevealed) {
+					continue
+				}
+				from := positions[room]
+				offset := directionOffsets[direction]
+				position := freePoint(point{from.x + offset.x, from.y + offset.y}, taken)
+				positions[exit.To] = position
+				taken[position] = true
+				order = append(order, exit.To)
+			}
+		}
+	}
+	return positions
+}
+
+// freePoint is want, or the nearest point to it that no room has taken.
+func freePoint(want point, taken map[point]bool) point {
+	if !taken[want] {
+		return want
+	}
+	for ring := 1; ; ring++ {
+		for dy := -ring; dy <= ring; dy++ {
+			for dx := -ring; dx <= ring; dx++ {
+				candidate := point{want.x + dx, want.y + dy}
+				if max(abs(dx), abs(dy)) == ring && !taken[candidate] {
+					return candidate
+				}
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// mapLayout lays out what the player has explored of the world that starts
+// at origin.
+func (p *Player) mapLayout(origin *Room) MapLayout {
+	positions := layOut(origin)
+	explored := func(room *Room) bool { return room == p.CurrentRoom || p.visited[room] }
+
+	var rooms []*Room
+	index := make(map[*Room]int)
+	add := func(room *Room) {
+		if _, ok := index[room]; !ok {
+			index[room] = len(rooms)
+			rooms = append(rooms, room)
+		}
+	}
+	exits := []MapExit{}
+	for _, room := range placedInOrder(positions) {
+		if !explored(room) {
+			continue
+		}
+		add(room)
+		for _, direction := range sortedNames(room.Exits) {
+			exit := room.Exits[direction]
+			if exit.Hidden {
+				continue
+			}
+			add(exit.To)
+			exits = append(exits, MapExit{From: index[room], To: index[exit.To], Direction: direction, Locked: !exit.opensFor(p)})
+		}
+	}
+
+	layout := MapLayout{Rooms: []MapRoom{}, Exits: exits}
+	if len(rooms) == 0 {
+		return layout
+	}
+	minX, minY := positions[rooms[0]].x, positions[rooms[0]].y
+	for _, room := range rooms {
+		minX, minY = min(minX, positions[room].x), min(minY, positions[room].y)
+	}
+	for _, room := range rooms {
+		mapRoom := MapRoom{X: positions[room].x - minX, Y: positions[room].y - minY, Visited: explored(room), Current: room == p.CurrentRoom}
+		if mapRoom.Visited {
+			mapRoom.Name = room.Name
+		}
+		layout.Width, layout.Height = max(layout.Width, mapRoom.X+1), max(layout.Height, mapRoom.Y+1)
+		layout.Rooms = append(layout.Rooms, mapRoom)
+	}
+	return layout
+}
+
+// placedInOrder lists the rooms from top to bottom and left to right.
+func placedInOrder(positions map[*Room]point) []*Room {
+	var rooms []*Room
+	for room := range positions {
+		rooms = append(rooms, room)
+	}
+	slices.SortFunc(rooms, func(a, b *Room) int {
+		if positions[a].y != positions[b].y {
+			return positions[a].y - positions[b].y
+		}
+		if positions[a].x != positions[b].x {
+			return positions[a].x - positions[b].x
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rooms
+}
+
+// label is how a room is written on the ASCII map.
+func (r MapRoom) label() string {
+	switch {
+	case !r.Visited:
+		return "[?]"
+	case r.Current:
+		return "[*" + r.Name + "*]"
+	}
+	return "[" + r.Name + "]"
+}
+
+// ASCII draws the layout with a box for every room, "---" and "|" for exits
+// between neighbouring rooms, and an x on locked ones. It also returns the
+// exits that join rooms that aren't next to each other, which can't be
+// drawn.
+func (layout MapLayout) ASCII() (string, []MapExit) {
+	width := 0
+	grid := make(map[point]MapRoom)
+	for _, room := range layout.Rooms {
+		width = max(width, len(room.label()))
+		grid[point{room.X, room.Y}] = room
+	}
+	across := make(map[point]string)
+	down := make(map[point]string)
+	var undrawn []MapExit
+	for _, exit := range layout.Exits {
+		from, to := layout.Rooms[exit.From], layout.Rooms[exit.To]
+		corner := point{min(from.X, to.X), min(from.Y, to.Y)}
+		switch {
+		case from.Y == to.Y && abs(from.X-to.X) == 1:
+			across[corner] = joinSymbol(across[corner], exit.Locked, "---", "-x-")
+		case from.X == to.X && abs(from.Y-to.Y) == 1:
+			down[corner] = joinSymbol(down[corner], exit.Locked, "|", "x")
+		default:
+			undrawn = append(undrawn, exit)
+		}
+	}
+
+	var out strings.Builder
+	for y := 0; y < layout.Height; y++ {
+		var line, below strings.Builder
+		for x := 0; x < layout.Width; x++ {
+			if room, ok := grid[point{x, y}]; ok {
+				line.WriteString(pad(room.label(), width, fill(across[point{x - 1, y}]), fill(across[point{x, y}])))
+			} else {
+				line.WriteString(strings.Repeat(" ", width))
+			}
+			below.WriteString(pad(down[point{x, y}], width, " ", " "))
+			if x < layout.Width-1 {
+				line.WriteString(pad(across[point{x, y}], 3, " ", " "))
+				below.WriteString("   ")
+			}
+		}
+		out.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		if y < layout.Height-1 {
+			out.WriteString(strings.TrimRight(below.String(), " ") + "\n")
+		}
+	}
+	return out.String(), undrawn
+}
+
+// joinSymbol draws an exit over one already drawn the other way, showing it
+// locked if either way is.
+func joinSymbol(drawn string, locked bool, open string, shut string) string {
+	if locked || drawn == shut {
+		return shut
+	}
+	return open
+}
+
+// pad centres text in width, filling the space either side with left and
+// right so that exits reach the rooms they join.
+func pad(text string, width int, left string, right string) string {
+	padding := max(width-len(text), 0)
+	return strings.Repeat(left, padding/2) + text + strings.Repeat(right, padding-padding/2)
+}
+
+func fill(exit string) string {
+	if exit == "" {
+		return " "
+	}
+	return "-"
+}
+
+// SVG draws the layout for the web client. The current room has the class
+// "current", rooms not yet visited "unvisited" and locked exits "locked".
+func (layout MapLayout) SVG() string {
+	const cellWidth, cellHeight, roomWidth, roomHeight = 160, 90, 120, 40
+	centreOf := func(room MapRoom) (int, int) {
+		return room.X*cellWidth + cellWidth/2, room.Y*cellHeight + cellHeight/2
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		layout.Width*cellWidth, layout.Height*cellHeight, layout.Width*cellWidth, layout.Height*cellHeight)
+	for _, exit := range layout.Exits {
+		x1, y1 := centreOf(layout.Rooms[exit.From])
+		x2, y2 := centreOf(layout.Rooms[exit.To])
+		class, dashes := "exit", ""
+		if exit.Locked {
+			class, dashes = "exit locked", ` stroke-dasharray="4"`
+		}
+		fmt.Fprintf(&out, `<line class="%s" x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"%s/>`+"\n",
+			class, x1, y1, x2, y2, dashes)
+	}
+	for _, room := range layout.Rooms {
+		x, y := centreOf(room)
+		class, name := "room", room.Name
+		switch {
+		case !room.Visited:
+			class, name = "room unvisited", "?"
+		case room.Current:
+			class = "room current"
+		}
+		fmt.Fprintf(&out, `<g class="%s"><rect x="%d" y="%d" width="%d" height="%d" fill="white" stroke="black"/>`,
+			class, x-roomWidth/2, y-roomHeight/2, roomWidth, roomHeight)
+		fmt.Fprintf(&out, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="middle">%s</text></g>`+"\n",
+			x, y, html.EscapeString(name))
+	}
+	out.WriteString("</svg>\n")
+	return out.String()
+}
+
+// DrawMap is the ASCII map of what the player has explored, with a key.
+func (p *Player) DrawMap(origin *Room) string {
+	layout := p.mapLayout(origin)
+	drawn, undrawn := layout.ASCII()
+	var out strings.Builder
+	out.WriteString("{code}" + escapeMarkup(drawn) + "{/code}\n")
+	for _, exit := range undrawn {
+		to := layout.Rooms[exit.To].Name
+		if to == "" {
+			to = "?"
+		}
+		out.WriteString(p.text("map.far", "%s leads %s to %s\n", layout.Rooms[exit.From].Name, exit.Direction, to))
+	}
+	out.WriteString(p.text("map.key", "* you are here, x locked, ? not explored yet\n"))
+	return out.String()
+}
+
+// Map lays out what the player has explored, for clients that draw it
+// themselves.
+func (game *Game) Map(playerID string) (MapLayout, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return MapLayout{}, ErrUnknownPlayer
+	}
+	return player.mapLayout(game.staffRoom), nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func joinWorld(t *testing.T, handler http.Handler, worldID string, playerName string) SessionCreated {
+	t.Helper()
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"player_name":"`+playerName+`","world_id":"`+worldID+`"}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d joining world %s, got %d", http.StatusCreated, worldID, recorder.Code)
+	}
+	var created SessionCreated
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestPlayersInTheSameWorldSeeEachOther(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+
+	//Act
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"look"}`)
+
+	//Assert
+	if grace.WorldID != ada.WorldID {
+		t.Errorf("Expected to join world %s, got %s", ada.WorldID, grace.WorldID)
+	}
+	if !strings.Contains(recorder.Body.String(), "Also here:\\n- Grace") {
+		t.Errorf("Expected Grace to be listed in the room, got %s", recorder.Body.String())
+	}
+}
+
+func TestItemTakenByOnePlayerIsGoneForOthers(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"approach","args":["kettle"]}`)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"take","args":["tea"]}`)
+
+	//Act
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+grace.ID+"/commands", `{"command":"take","args":["tea"]}`)
+
+	//Assert
+	var response model.GameResponse
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response.Message != "You can't take tea\n" {
+		t.Errorf("Expected the tea to be gone, got %q", response.Message)
+	}
+}
+
+func TestEventTriggeredByOnePlayerAdvancesTheWorld(t *testing.T) {
+	//Arrange
+	s := newServer()
+	handler := s.handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+
+	//Act
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"approach","args":["kettle"]}`)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"take","args":["tea"]}`)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"approach","args":["rosie"]}`)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"use","args":["tea"]}`)
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+grace.ID+"/commands", `{"command":"look"}`)
+	recorder := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+grace.ID+"/commands", `{"command":"take","args":["lanyard"]}`)
+
+	//Assert
+	if !strings.Contains(recorder.Body.String(), "lanyard has been added to your inventory") {
+		t.Errorf("Expected Grace to take the lanyard Ada earned, got %s", recorder.Body.String())
+	}
+	session, _ := s.sessions.Get(grace.ID)
+	if summary := session.Summary(); len(summary.TriggeredEvents) != 1 || summary.TriggeredEvents[0] != "get-your-lanyard" {
+		t.Errorf("Expected get-your-lanyard in Grace's progress, got %v", summary.TriggeredEvents)
+	}
+}
+
+func TestExitingLeavesTheWorldToOthers(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+
+	//Act
+	exited := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+ada.ID+"/commands", `{"command":"exit"}`)
+	next := performRequest(handler, http.MethodPost, "/api/v1/sessions/"+grace.ID+"/commands", `{"command":"look"}`)
+
+	//Assert
+	if !strings.Contains(exited.Body.String(), `"game_over":true`) {
+		t.Errorf("Expected the game to be over for Ada, got %s", exited.Body.String())
+	}
+	if next.Code != http.StatusOK || strings.Contains(next.Body.String(), "Ada") {
+		t.Errorf("Expected Grace to play on alone, got %d %s", next.Code, next.Body.String())
+	}
+}
+
+func TestJoiningAWorldRejectsUnknownWorldsAndTakenNames(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+
+	//Act
+	unknown := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"player_name":"Grace","world_id":"missing"}`)
+	taken := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"player_name":"anonymous","world_id":"`+ada.WorldID+`"}`)
+
+	//Assert
+	if unknown.Code != http.StatusNotFound || decodeError(t, unknown).Code != errorWorldNotFound {
+		t.Errorf("Expected %d %s, got %d", http.StatusNotFound, errorWorldNotFound, unknown.Code)
+	}
+	if taken.Code != http.StatusConflict || decodeError(t, taken).Code != errorNameTaken {
+		t.Errorf("Expected %d %s, got %d", http.StatusConflict, errorNameTaken, taken.Code)
+	}
+}
+
+func TestConcurrentPlayersCannotTakeTheSameItem(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	var players []string
+	for _, name := range []string{"Ada", "Grace", "Linus", "Ken"} {
+		id, err := game.Join(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players = append(players, id)
+	}
+	game.RunGameAs(players[0], model.PlayerInput{Command: "approach", Args: []string{"kettle"}})
+	game.RunGameAs(players[0], model.PlayerInput{Command: "leave"})
+
+	//Act
+	responses := make([]model.GameResponse, len(players))
+	var wg sync.WaitGroup
+	for i, id := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = game.RunGameAs(id, model.PlayerInput{Command: "take", Args: []string{"tea"}})
+		}()
+	}
+	wg.Wait()
+
+	//Assert
+	taken := 0
+	for _, response := range responses {
+		if strings.Contains(response.Message, "has been added to your inventory") {
+			taken++
+		}
+	}
+	if taken != 1 {
+		t.Errorf("Expected exactly 1 player to take the tea, got %d", taken)
+	}
+}
+
+func TestGivingAnItemToAnotherPlayer(t *testing.T) {
+	//Arrange
+	handler := newServer().handler()
+	ada := createTestSession(t, handler)
+	grace := joinWorld(t, handler, ada.WorldID, "Grace")
+	runAs(handler, ada, `{"command":"approach","args":["kettle"]}`)
+	runAs(handler, ada, `{"command":"take","args":["tea"]}`)
+
+	//Act
+	given := responseMessage(t, runAs(handler, ada, `{"command":"give","args":["tea","to","Grace"]}`))
+	received := responseMessage(t, runAs(handler, grace, `{"command":"inventory"}`))
+
+	//Assert
+	if given != "You gave tea to Grace.\n" {
+		t.Errorf("Expected the tea to be given, got %q", given)
+	}
+	if !strings.HasPrefix(received, "anonymous gave you tea.\n\n") || !strings.Contains(received, "- tea:") {
+		t.Errorf("Expected Grace to be told and to hold the tea, got %q", received)
+	}
+}
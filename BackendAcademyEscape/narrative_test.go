@@ -0,0 +1,103 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestComputerCountsDownRemainingAttempts(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	run(game, "move", "south")
+	run(game, "approach", "computer")
+
+	//Act
+	run(game, "waterfall")
+	run(game, "leave")
+	approached := run(game, "approach", "computer")
+
+	//Assert
+	if !strings.Contains(approached.Message, "Remaining attempts: 9.\n") {
+		t.Errorf("Expected the computer to show 9 attempts left, got %q", approached.Message)
+	}
+}
+
+func TestDescriptionsAdaptToPuzzleEvents(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	unlockAlansComputer(game)
+
+	//Act
+	alan := run(game, "approach", "alan")
+	computer := run(game, "approach", "computer")
+
+	//Assert
+	if alan.Message != "You've cracked the password! Impressive work..." {
+		t.Errorf("Expected Alan to know the computer is unlocked, got %q", alan.Message)
+	}
+	if !strings.HasPrefix(computer.Message, "function completeTask(pile)") {
+		t.Errorf("Expected the computer to show the code, got %q", computer.Message)
+	}
+}
+
+func TestStoryTextCanUseThePlayersState(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupWorld()
+	addFridge(game)
+	addCupboard(game)
+	id, _ := game.Join("Sam")
+	served := &model.Event{Description: "served", Outcome: `{{if has "tea"}}Still holding the tea, {{.Player}}?{{else}}Well done, {{.Player}}.{{end}}` + "\n"}
+	game.AddRecipe(&model.Recipe{Inputs: []string{"biscuits", "milk"}, Keeps: []string{"biscuits"}, Outcome: "It's {{.Time}} in {{.Room}}.\n", Event: served})
+	for _, command := range [][]string{{"approach", "kettle"}, {"take", "tea"}, {"leave"}, {"open", "fridge"}, {"take", "milk", "from", "fridge"}, {"examine", "cupboard"}} {
+		game.RunGameAs(id, model.PlayerInput{Command: command[0], Args: command[1:]})
+	}
+
+	//Act
+	combined := game.RunGameAs(id, model.PlayerInput{Command: "combine", Args: []string{"biscuits", "milk"}})
+
+	//Assert
+	if !strings.HasPrefix(combined.Message, "It's 09:") || !strings.Contains(combined.Message, "Still holding the tea, Sam?\n") {
+		t.Errorf("Expected the outcome to be rendered with the game state, got %q", combined.Message)
+	}
+}
+
+func TestItemDescriptionsCanUseTheGameState(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddItem("break-room", &model.Item{Name: "mug", Description: `{{if happened "get-your-lanyard"}}Rosie's empty mug.{{else}}A clean mug.{{end}} It holds {{.Item.capacity}}ml.`, Weight: 1, Properties: model.Properties{"capacity": 300}})
+
+	//Act
+	before := run(game, "examine", "mug")
+	giveRosieTea(game)
+	after := run(game, "examine", "mug")
+
+	//Assert
+	if before.Message != "A clean mug. It holds 300ml.\n" {
+		t.Errorf("Expected the mug before Rosie has her tea, got %q", before.Message)
+	}
+	if after.Message != "Rosie's empty mug. It holds 300ml.\n" {
+		t.Errorf("Expected the mug after Rosie has her tea, got %q", after.Message)
+	}
+}
+
+func TestBrokenTemplatesFallBackToThePlainDescription(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddItem("break-room", &model.Item{Name: "mug", Description: "A clean mug.", Weight: 1, Detail: &model.Detail{First: "{{.Nonsense}}"}})
+
+	//Act
+	examined := run(game, "examine", "mug")
+
+	//Assert
+	if examined.Message != "A clean mug.\n" {
+		t.Errorf("Expected the plain description instead of the template, got %q", examined.Message)
+	}
+}
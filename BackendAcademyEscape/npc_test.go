@@ -0,0 +1,128 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func run(game *model.Game, command string, args ...string) model.GameResponse {
+	return game.RunGame(model.PlayerInput{Command: command, Args: args})
+}
+
+// addRosiesPatrol has Rosie walk between the break room and the coding lab
+// once she has had her tea.
+func addRosiesPatrol(game *model.Game) {
+	game.AddBehaviour("rosie", &model.After{Event: "get-your-lanyard", Then: &model.Patrol{Route: []string{"break-room", "coding-lab"}, Every: 6}})
+}
+
+// giveRosieTea plays until Rosie has the tea, which starts any patrol.
+func giveRosieTea(game *model.Game) {
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+	run(game, "approach", "rosie")
+	run(game, "use", "tea")
+}
+
+// waitFor looks around until a response contains text.
+func waitFor(t *testing.T, game *model.Game, text string) model.GameResponse {
+	t.Helper()
+	for i := 0; i < 12; i++ {
+		if response := run(game, "look"); strings.Contains(response.Message, text) {
+			return response
+		}
+	}
+	t.Fatalf("Expected %q within 12 turns", text)
+	return model.GameResponse{}
+}
+
+func TestRosieCatchesAThiefInFrontOfHer(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "approach", "sofa")
+	run(game, "leave")
+
+	//Act
+	response := run(game, "take", "abandoned-lanyard")
+
+	//Assert
+	if !response.GameOver || !strings.Contains(response.Message, "Rosie caught you in the act") {
+		t.Errorf("Expected Rosie to catch the thief, got %+v", response)
+	}
+}
+
+func TestRosieWalksInOnAThief(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addRosiesPatrol(game)
+	giveRosieTea(game)
+	run(game, "approach", "sofa")
+	waitFor(t, game, "rosie leaves for the coding-lab.")
+
+	//Act
+	taken := run(game, "take", "abandoned-lanyard")
+	waitFor(t, game, "rosie walks in.")
+	caught := run(game, "look")
+
+	//Assert
+	if taken.GameOver {
+		t.Errorf("Expected to get away with it while Rosie is away, got %+v", taken)
+	}
+	if !caught.GameOver || !strings.Contains(caught.Message, "Rosie caught you in the act") {
+		t.Errorf("Expected Rosie to catch the thief once she is back, got %+v", caught)
+	}
+}
+
+func TestReturningTheLanyardBeforeRosieIsBack(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addRosiesPatrol(game)
+	giveRosieTea(game)
+	run(game, "approach", "sofa")
+	waitFor(t, game, "rosie leaves for the coding-lab.")
+	run(game, "take", "abandoned-lanyard")
+
+	//Act
+	returned := run(game, "give", "abandoned-lanyard", "to", "sofa")
+	waitFor(t, game, "rosie walks in.")
+	back := run(game, "look")
+
+	//Assert
+	if !strings.HasPrefix(returned.Message, "You slip the lanyard back") {
+		t.Errorf("Expected the sofa to take the lanyard back, got %q", returned.Message)
+	}
+	if back.GameOver {
+		t.Errorf("Expected Rosie to find nothing amiss, got %+v", back)
+	}
+}
+
+func TestCatFollowsAndAlanGreets(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddBehaviour("cat", &model.Follow{Message: "The cat pads along after you, tail held high."})
+	game.AddBehaviour("alan", &model.Greet{Message: "Alan glances up from his screen. \"Ah, there you are.\""})
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	run(game, "approach", "cat")
+
+	//Act
+	moved := run(game, "move", "south")
+	again := run(game, "look")
+
+	//Assert
+	for _, expected := range []string{"cat walks in.", "The cat pads along after you", "Alan glances up from his screen."} {
+		if !strings.Contains(moved.Message, expected) {
+			t.Errorf("Expected %q on entering the coding lab, got %q", expected, moved.Message)
+		}
+	}
+	if strings.Contains(again.Message, "Alan glances up") {
+		t.Errorf("Expected Alan to greet only once, got %q", again.Message)
+	}
+	if !strings.Contains(again.Message, "- cat\n") {
+		t.Errorf("Expected the cat to be in the coding lab, got %q", again.Message)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const openAPIVersion = "3.0.3"
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (s *server) getOpenAPI(writer http.ResponseWriter, request *http.Request) {
+	writeJSON(writer, http.StatusOK, buildOpenAPI(s.routes()))
+}
+
+// buildOpenAPI documents the given routes as an OpenAPI document. Schemas
+// are derived from the Go types the handlers decode and encode, so adding a
+// field to a response type is reflected here without further changes.
+func buildOpenAPI(routes []route) map[string]any {
+	schemas := make(map[string]any)
+	paths := make(map[string]any)
+
+	for _, r := range routes {
+		operations, ok := paths[r.Pattern].(map[string]any)
+		if !ok {
+			operations = make(map[string]any)
+			paths[r.Pattern] = operations
+		}
+		operations[strings.ToLower(r.Method)] = buildOperation(r, schemas)
+	}
+
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "Academy Escape API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"facilitatorToken": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+func buildOperation(r route, schemas map[string]any) map[string]any {
+	var parameters []any
+	for _, match := range pathParamPattern.FindAllStringSubmatch(r.Pattern, -1) {
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	for _, param := range r.Query {
+		parameters = append(parameters, map[string]any{
+			"name":        param.Name,
+			"in":          "query",
+			"description": param.Description,
+			"required":    param.Required,
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+
+	responses := make(map[string]any)
+	for status, body := range r.Responses {
+		response := map[string]any{"description": http.StatusText(status)}
+		if stream, ok := body.(eventStream); ok {
+			response["content"] = map[string]any{
+				"text/event-stream": map[string]any{"schema": schemaFor(reflect.TypeOf(stream.Event), schemas)},
+			}
+		} else if _, ok := body.(svgImage); ok {
+			response["content"] = map[string]any{
+				"image/svg+xml": map[string]any{"schema": map[string]any{"type": "string"}},
+			}
+		} else if body != nil {
+			response["content"] = jsonContent(reflect.TypeOf(body), schemas)
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+
+	operation := map[string]any{
+		"summary":   r.Summary,
+		"responses": responses,
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if r.Request != nil {
+		operation["requestBody"] = map[string]any{
+			"required": !r.RequestOptional,
+			"content":  jsonContent(reflect.TypeOf(r.Request), schemas),
+		}
+	}
+	if r.Facilitator {
+		operation["security"] = []any{map[string]any{"facilitatorToken": []any{}}}
+	}
+	return operation
+}
+
+func jsonContent(t reflect.Type, schemas map[string]any) map[string]any {
+	return map[string]any{
+		"application/json": map[string]any{"schema": schemaFor(t, schemas)},
+	}
+}
+
+// schemaFor returns the schema of t, registering named structs as
+// components and referring to them by $ref.
+func schemaFor(t reflect.Type, schemas map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), schemas)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		if _, ok := schemas[t.Name()]; !ok {
+			schemas[t.Name()] = nil
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	addStructFields(t, schemas, properties, &required)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func addStructFields(t reflect.Type, schemas map[string]any, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addStructFields(field.Type, schemas, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func fetchOpenAPI(t *testing.T, handler http.Handler) map[string]any {
+	t.Helper()
+	recorder := performRequest(handler, http.MethodGet, "/openapi.json", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for /openapi.json, got %d", http.StatusOK, recorder.Code)
+	}
+	var spec map[string]any
+	if err := json.NewDecoder(recorder.Body).Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// resolveSchema follows a $ref into the document's component schemas.
+func resolveSchema(spec map[string]any, schema map[string]any) map[string]any {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	return spec["components"].(map[string]any)["schemas"].(map[string]any)[name].(map[string]any)
+}
+
+// checkBodyMatchesSchema reports fields the handler wrote that the schema
+// does not declare, and required fields the handler left out.
+func checkBodyMatchesSchema(t *testing.T, context string, spec map[string]any, schema map[string]any, body any) {
+	t.Helper()
+	schema = resolveSchema(spec, schema)
+
+	switch schema["type"] {
+	case "object":
+		object, ok := body.(map[string]any)
+		if !ok {
+			t.Errorf("%s: expected an object, got %v", context, body)
+			return
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		if properties == nil {
+			return
+		}
+		for key, value := range object {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				t.Errorf("%s: field %q is not in the specification", context, key)
+				continue
+			}
+			checkBodyMatchesSchema(t, context+"."+key, spec, property, value)
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				t.Errorf("%s: required field %q is missing", context, name)
+			}
+		}
+	case "array":
+		items, ok := body.([]any)
+		if !ok {
+			t.Errorf("%s: expected an array, got %v", context, body)
+			return
+		}
+		for i, item := range items {
+			checkBodyMatchesSchema(t, context+"["+strconv.Itoa(i)+"]", spec, schema["items"].(map[string]any), item)
+		}
+	case "string":
+		if _, ok := body.(string); !ok {
+			t.Errorf("%s: expected a string, got %v", context, body)
+		}
+	case "boolean":
+		if _, ok := body.(bool); !ok {
+			t.Errorf("%s: expected a boolean, got %v", context, body)
+		}
+	case "integer", "number":
+		if _, ok := body.(float64); !ok {
+			t.Errorf("%s: expected a number, got %v", context, body)
+		}
+	}
+}
+
+// checkEventStreamMatchesSchema checks the data of every event in an SSE body.
+func checkEventStreamMatchesSchema(t *testing.T, context string, spec map[string]any, schema map[string]any, body string) {
+	t.Helper()
+	events := 0
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		events++
+		var event any
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Errorf("%s: expected JSON event data, got %q", context, data)
+			continue
+		}
+		checkBodyMatchesSchema(t, context, spec, schema, event)
+	}
+	if events == 0 {
+		t.Errorf("%s: expected at least one event, got %q", context, body)
+	}
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	//Arrange
+	s := newServer()
+
+	//Act
+	spec := fetchOpenAPI(t, s.handler())
+
+	//Assert
+	if spec["openapi"] != openAPIVersion {
+		t.Errorf("Expected openapi %s, got %v", openAPIVersion, spec["openapi"])
+	}
+	paths := spec["paths"].(map[string]any)
+	documented := 0
+	for _, operations := range paths {
+		documented += len(operations.(map[string]any))
+	}
+	if documented != len(s.routes()) {
+		t.Errorf("Expected %d documented operations, got %d", len(s.routes()), documented)
+	}
+	for _, r := range s.routes() {
+		operations, ok := paths[r.Pattern].(map[string]any)
+		if !ok || operations[strings.ToLower(r.Method)] == nil {
+			t.Errorf("Expected %s %s to be documented", r.Method, r.Pattern)
+		}
+	}
+}
+
+// TestHandlersMatchOpenAPI drives every documented response through the real
+// handlers and checks the status and body against the served document, so a
+// handler that starts returning an undocumented status or field fails here.
+func TestHandlersMatchOpenAPI(t *testing.T) {
+	//Arrange
+	s := newServer()
+	s.facilitatorToken = "secret"
+	handler := s.handler()
+	spec := fetchOpenAPI(t, handler)
+	paths := spec["paths"].(map[string]any)
+
+	liveSession := createTestSession(t, handler)
+	live := liveSession.ID
+	finished := createTestSession(t, handler).ID
+	performRequest(handler, http.MethodPost, "/api/v1/sessions/"+finished+"/commands", `{"command":"exit"}`)
+	deleted := createTestSession(t, handler).ID
+	timedRecorder := performRequest(handler, http.MethodPost, "/api/v1/sessions", `{"time_limit_seconds":600}`)
+	var timed SessionCreated
+	json.NewDecoder(timedRecorder.Body).Decode(&timed)
+
+	requests := []struct {
+		method  string
+		pattern string
+		path    string
+		body    string
+		stream  bool
+		token   bool
+	}{
+		{http.MethodPost, "/api/v1/sessions", "/api/v1/sessions", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}", "/api/v1/sessions/" + live, "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}", "/api/v1/sessions/missing", "", false, false},
+		{http.MethodDelete, "/api/v1/sessions/{id}", "/api/v1/sessions/" + deleted, "", false, false},
+		{http.MethodDelete, "/api/v1/sessions/{id}", "/api/v1/sessions/missing", "", false, false},
+		{http.MethodPost, "/api/v1/sessions/{id}/commands", "/api/v1/sessions/" + live + "/commands", `{"command":"look"}`, false, false},
+		{http.MethodPost, "/api/v1/sessions/{id}/commands", "/api/v1/sessions/" + live + "/commands", `{"command":""}`, false, false},
+		{http.MethodPost, "/api/v1/sessions/{id}/commands", "/api/v1/sessions/missing/commands", `{"command":"look"}`, false, false},
+		{http.MethodPost, "/api/v1/sessions/{id}/commands", "/api/v1/sessions/" + finished + "/commands", `{"command":"look"}`, false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/ws", "/api/v1/sessions/" + live + "/ws", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/ws", "/api/v1/sessions/missing/ws", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/events", "/api/v1/sessions/" + live + "/events", "", true, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/events", "/api/v1/sessions/" + live + "/events?last_event_id=soon", "", true, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/events", "/api/v1/sessions/missing/events", "", true, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/actions", "/api/v1/sessions/" + live + "/actions?command=approach", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/actions", "/api/v1/sessions/" + live + "/actions", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/actions", "/api/v1/sessions/missing/actions?command=take", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/actions", "/api/v1/sessions/" + finished + "/actions?command=take", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/map", "/api/v1/sessions/" + live + "/map", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/map", "/api/v1/sessions/missing/map", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/map.svg", "/api/v1/sessions/" + live + "/map.svg", "", false, false},
+		{http.MethodGet, "/api/v1/sessions/{id}/map.svg", "/api/v1/sessions/missing/map.svg", "", false, false},
+		{http.MethodPost, "/api/v1/sessions", "/api/v1/sessions", `{"player_name":42}`, false, false},
+		{http.MethodPost, "/api/v1/sessions", "/api/v1/sessions", `{"world_id":"missing"}`, false, false},
+		{http.MethodPost, "/api/v1/sessions", "/api/v1/sessions", `{"world_id":"` + liveSession.WorldID + `"}`, false, false},
+		{http.MethodPost, "/api/v1/sessions", "/api/v1/sessions", `{"player_name":"anonymous","world_id":"` + liveSession.WorldID + `"}`, false, false},
+		{http.MethodGet, "/api/v1/facilitator/sessions", "/api/v1/facilitator/sessions", "", false, true},
+		{http.MethodGet, "/api/v1/facilitator/sessions", "/api/v1/facilitator/sessions", "", false, false},
+		{http.MethodGet, "/api/v1/facilitator/sessions/{id}/transcript", "/api/v1/facilitator/sessions/" + live + "/transcript?after=1", "", false, true},
+		{http.MethodGet, "/api/v1/facilitator/sessions/{id}/transcript", "/api/v1/facilitator/sessions/" + live + "/transcript?after=last", "", false, true},
+		{http.MethodGet, "/api/v1/facilitator/sessions/{id}/transcript", "/api/v1/facilitator/sessions/" + live + "/transcript", "", false, false},
+		{http.MethodGet, "/api/v1/facilitator/sessions/{id}/transcript", "/api/v1/facilitator/sessions/missing/transcript", "", false, true},
+		{http.MethodPost, "/api/v1/facilitator/sessions/{id}/messages", "/api/v1/facilitator/sessions/" + live + "/messages", `{"message":"Try the kettle."}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/sessions/{id}/messages", "/api/v1/facilitator/sessions/" + live + "/messages", `{"message":""}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/sessions/{id}/messages", "/api/v1/facilitator/sessions/" + live + "/messages", `{"message":"hi"}`, false, false},
+		{http.MethodPost, "/api/v1/facilitator/sessions/{id}/messages", "/api/v1/facilitator/sessions/missing/messages", `{"message":"hi"}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/worlds/{id}/countdown", "/api/v1/facilitator/worlds/" + timed.WorldID + "/countdown", `{"action":"extend","seconds":60}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/worlds/{id}/countdown", "/api/v1/facilitator/worlds/" + timed.WorldID + "/countdown", `{"action":"extend"}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/worlds/{id}/countdown", "/api/v1/facilitator/worlds/" + timed.WorldID + "/countdown", `{"action":"pause"}`, false, false},
+		{http.MethodPost, "/api/v1/facilitator/worlds/{id}/countdown", "/api/v1/facilitator/worlds/missing/countdown", `{"action":"pause"}`, false, true},
+		{http.MethodPost, "/api/v1/facilitator/worlds/{id}/countdown", "/api/v1/facilitator/worlds/" + liveSession.WorldID + "/countdown", `{"action":"pause"}`, false, true},
+		{http.MethodPost, "/api/v1/sessions/{id}/commands", "/api/v1/sessions/" + timed.ID + "/commands", `{"command":"look"}`, false, false},
+	}
+
+	exercised := make(map[string]bool)
+
+	for _, r := range requests {
+		//Act
+		request := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+		if r.token {
+			request.Header.Set("Authorization", "Bearer "+s.facilitatorToken)
+		}
+		if r.stream {
+			// A cancelled stream writes what it has buffered and returns.
+			ctx, cancel := context.WithCancel(request.Context())
+			cancel()
+			request = request.WithContext(ctx)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		//Assert
+		context := r.method + " " + r.path
+		operation, ok := paths[r.pattern].(map[string]any)[strings.ToLower(r.method)].(map[string]any)
+		if !ok {
+			t.Errorf("%s: no operation documented for %s %s", context, r.method, r.pattern)
+			continue
+		}
+		status := strconv.Itoa(recorder.Code)
+		exercised[r.method+" "+r.pattern+" "+status] = true
+
+		response, ok := operation["responses"].(map[string]any)[status].(map[string]any)
+		if !ok {
+			t.Errorf("%s: status %s is not documented", context, status)
+			continue
+		}
+		content, hasContent := response["content"].(map[string]any)
+		if !hasContent {
+			if recorder.Body.Len() != 0 {
+				t.Errorf("%s: documented without a body but got %q", context, recorder.Body.String())
+			}
+			continue
+		}
+		if stream, ok := content["text/event-stream"].(map[string]any); ok {
+			checkEventStreamMatchesSchema(t, context, spec, stream["schema"].(map[string]any), recorder.Body.String())
+			continue
+		}
+		if _, ok := content["image/svg+xml"]; ok {
+			if !strings.HasPrefix(recorder.Body.String(), "<svg") || recorder.Header().Get("Content-Type") != "image/svg+xml" {
+				t.Errorf("%s: expected an SVG image, got %q", context, recorder.Body.String())
+			}
+			continue
+		}
+		var body any
+		if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+			t.Errorf("%s: expected a JSON body, got %q", context, recorder.Body.String())
+			continue
+		}
+		schema := content["application/json"].(map[string]any)["schema"].(map[string]any)
+		checkBodyMatchesSchema(t, context, spec, schema, body)
+	}
+
+	// Upgrades need a real connection and are covered by the WebSocket tests.
+	unexercisable := map[int]bool{http.StatusInternalServerError: true, http.StatusSwitchingProtocols: true}
+
+	var missing []string
+	for _, r := range s.routes() {
+		for status := range r.Responses {
+			key := r.Method + " " + r.Pattern + " " + strconv.Itoa(status)
+			if !unexercisable[status] && !exercised[key] {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		t.Errorf("Documented response %s was not exercised", key)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import "sync"
+
+const (
+	frameCommand      = "command"
+	frameResponse     = "response"
+	frameNotification = "notification"
+	frameError        = "error"
+	frameClosed       = "closed"
+)
+
+const outboxBacklog = 100
+
+const subscriberBuffer = 64
+
+// Frame is a single JSON message on a streaming transport. Players send
+// command frames; the server sends responses, notifications, errors and a
+// final closed frame. Seq is the frame's position in the session's Outbox so
+// a client can resume after reconnecting.
+type Frame struct {
+	Type     string   `json:"type"`
+	Seq      int64    `json:"seq,omitempty"`
+	Command  string   `json:"command,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	GameOver bool     `json:"game_over,omitempty"`
+	Code     string   `json:"code,omitempty"`
+}
+
+// Sequenced is a value published to an Outbox along with its position.
+type Sequenced[T any] struct {
+	Seq   int64
+	Value T
+}
+
+// Outbox numbers the values published for a session, keeps the most recent
+// ones for replay and fans them out to every connected subscriber.
+type Outbox[T any] struct {
+	mu          sync.Mutex
+	lastSeq     int64
+	backlog     []Sequenced[T]
+	subscribers map[chan Sequenced[T]]struct{}
+	closed      bool
+}
+
+func NewOutbox[T any]() *Outbox[T] {
+	return &Outbox[T]{subscribers: make(map[chan Sequenced[T]]struct{})}
+}
+
+// Publish assigns the next sequence number to value and delivers it. A
+// subscriber that has fallen too far behind is disconnected rather than
+// allowed to block the game; it can reconnect and resume from the backlog.
+func (outbox *Outbox[T]) Publish(value T) int64 {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	if outbox.closed {
+		return outbox.lastSeq
+	}
+
+	outbox.lastSeq++
+	entry := Sequenced[T]{Seq: outbox.lastSeq, Value: value}
+
+	outbox.backlog = append(outbox.backlog, entry)
+	if len(outbox.backlog) > outboxBacklog {
+		outbox.backlog = outbox.backlog[len(outbox.backlog)-outboxBacklog:]
+	}
+
+	for subscriber := range outbox.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+			delete(outbox.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	return entry.Seq
+}
+
+// Subscribe registers a new subscriber and returns the values published
+// after lastSeen. complete is false when some of those values have already
+// been dropped from the backlog. The subscriber is closed straight away if
+// the outbox already is.
+func (outbox *Outbox[T]) Subscribe(lastSeen int64) (subscriber chan Sequenced[T], replay []Sequenced[T], complete bool) {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	complete = true
+	if len(outbox.backlog) > 0 && outbox.backlog[0].Seq > lastSeen+1 {
+		complete = false
+	}
+	for _, entry := range outbox.backlog {
+		if entry.Seq > lastSeen {
+			replay = append(replay, entry)
+		}
+	}
+
+	subscriber = make(chan Sequenced[T], subscriberBuffer)
+	if outbox.closed {
+		close(subscriber)
+		return subscriber, replay, complete
+	}
+	outbox.subscribers[subscriber] = struct{}{}
+	return subscriber, replay, complete
+}
+
+func (outbox *Outbox[T]) Unsubscribe(subscriber chan Sequenced[T]) {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	if _, ok := outbox.subscribers[subscriber]; ok {
+		delete(outbox.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// Close stops publishing and disconnects every subscriber once it has
+// received what was already published.
+func (outbox *Outbox[T]) Close() {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	outbox.closed = true
+	for subscriber := range outbox.subscribers {
+		delete(outbox.subscribers, subscriber)
+		close(subscriber)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the playthroughs")
+
+// playthroughScript reads a script of commands, one per line, like
+// "take tea". Blank lines and lines starting with # are skipped, and a
+// quoted line, like "cd /secret-files", is sent whole as the command, the
+// way passwords and terminal input are typed.
+func playthroughScript(t *testing.T, path string) []model.PlayerInput {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var inputs []model.PlayerInput
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			command, err := strconv.Unquote(line)
+			if err != nil {
+				t.Fatalf("%s: %v", line, err)
+			}
+			inputs = append(inputs, model.PlayerInput{Command: command})
+		default:
+			fields := strings.Fields(line)
+			inputs = append(inputs, model.PlayerInput{Command: fields[0], Args: fields[1:]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return inputs
+}
+
+// playThrough runs the commands against a fresh world and writes down every
+// command and response.
+func playThrough(inputs []model.PlayerInput) string {
+	game := &model.Game{}
+	game.SetupGame()
+
+	var transcript strings.Builder
+	for _, input := range inputs {
+		response := game.RunGame(input)
+		fmt.Fprintf(&transcript, "> %s\n", strings.Join(append([]string{input.Command}, input.Args...), " "))
+		transcript.WriteString(strings.TrimRight(response.Message, "\n") + "\n")
+		if response.GameOver {
+			transcript.WriteString("[game over]\n")
+		}
+		transcript.WriteString("\n")
+	}
+	return transcript.String()
+}
+
+// TestPlaythroughs plays every script in testdata/playthroughs and compares
+// the transcript with the script's golden file. Run with -update to rewrite
+// the golden files after changing what the game says.
+func TestPlaythroughs(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("testdata", "playthroughs", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) == 0 {
+		t.Fatal("Expected playthrough scripts in testdata/playthroughs")
+	}
+
+	for _, script := range scripts {
+		t.Run(strings.TrimSuffix(filepath.Base(script), ".txt"), func(t *testing.T) {
+			//Arrange
+			inputs := playthroughScript(t, script)
+			golden := strings.TrimSuffix(script, ".txt") + ".golden"
+
+			//Act
+			transcript := playThrough(inputs)
+
+			//Assert
+			if *update {
+				if err := os.WriteFile(golden, []byte(transcript), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Expected a golden file, run go test -run TestPlaythroughs -update to write it: %v", err)
+			}
+			if transcript != string(expected) {
+				t.Errorf("Expected the transcript in %s, got:\n%s\n%s", golden, transcript, firstDifference(string(expected), transcript))
+			}
+			if !strings.HasSuffix(transcript, "[game over]\n\n") {
+				t.Errorf("Expected %s to play until the game is over", script)
+			}
+		})
+	}
+}
+
+// firstDifference points at the first line where two transcripts differ.
+func firstDifference(expected string, got string) string {
+	expectedLines, gotLines := strings.Split(expected, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(expectedLines) && i < len(gotLines); i++ {
+		if expectedLines[i] != gotLines[i] {
+			return fmt.Sprintf("First difference on line %d:\nexpected %q\ngot      %q", i+1, expectedLines[i], gotLines[i])
+		}
+	}
+	return fmt.Sprintf("Expected %d lines, got %d", len(expectedLines), len(gotLines))
+}
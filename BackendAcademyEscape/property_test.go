@@ -0,0 +1,98 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+func TestTeaCoolsTurnByTurn(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+
+	//Act
+	hot := run(game, "inventory")
+	for i := 0; i < 10; i++ {
+		run(game, "look")
+	}
+	cooling := run(game, "inventory")
+	for i := 0; i < 5; i++ {
+		run(game, "look")
+	}
+	cold := run(game, "inventory")
+
+	//Assert
+	if !strings.Contains(hot.Message, "A steaming cup of Yorkshire tea") {
+		t.Errorf("Expected the tea to be hot, got %q", hot.Message)
+	}
+	if !strings.Contains(cooling.Message, "going cold") {
+		t.Errorf("Expected the tea to be cooling, got %q", cooling.Message)
+	}
+	if !strings.Contains(cold.Message, "stone cold") {
+		t.Errorf("Expected the tea to be cold, got %q", cold.Message)
+	}
+}
+
+func TestOnlyItemsInPlayChange(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	cooling := []model.Change{{Property: "temperature", By: -1}}
+	stashed := &model.Item{Name: "soup", Properties: model.Properties{"temperature": 80}, Changes: cooling}
+	boxed := &model.Item{Name: "pie", Properties: model.Properties{"temperature": 80}, Changes: cooling}
+	game.AddContainer("break-room", &model.Container{Name: "safe", Hidden: true, Items: []*model.Item{stashed}})
+	game.AddContainer("break-room", &model.Container{Name: "lunchbox", Closable: true, Closed: true, Items: []*model.Item{boxed}})
+
+	//Act
+	run(game, "look")
+	run(game, "look")
+
+	//Assert
+	if stashed.Properties["temperature"] != 80 {
+		t.Errorf("Expected nothing in a hidden container to change, got %v", stashed.Properties["temperature"])
+	}
+	if boxed.Properties["temperature"] != 78 {
+		t.Errorf("Expected things in a closed container to change, got %v", boxed.Properties["temperature"])
+	}
+}
+
+func TestInteractionsReadAndWearItemProperties(t *testing.T) {
+	//Arrange
+	opened := &model.Event{Description: "reader-opened", Outcome: "The door clicks open.\n"}
+	reader := &model.Entity{Name: "reader"}
+	keycard := &model.Item{Name: "keycard", Description: "A keycard with {{.Item.uses}} uses left.", Weight: 1, Properties: model.Properties{"charged": false, "uses": 2}}
+	room := model.Room{Name: "Room 1", Items: map[string]*model.Item{}, Entities: map[string]*model.Entity{"reader": reader}}
+	player := model.Player{CurrentRoom: &room, CurrentEntity: reader, Inventory: map[string]*model.Item{"keycard": keycard}, CarriedWeight: 1, AvailableWeight: 19,
+		Interactions: []*model.Interaction{{
+			ItemName:   "keycard",
+			EntityName: "reader",
+			Event:      opened,
+			Requires:   []model.Condition{{Property: "charged", Op: "=", Value: true}},
+			Refusal:    "The reader beeps angrily.\n",
+		}},
+	}
+
+	//Act
+	refused := player.Use("keycard", "reader", model.ConsoleDisplay{})
+	keycard.SetProperty("charged", true)
+	first := player.Use("keycard", "reader", model.ConsoleDisplay{})
+	description := keycard.GetDescription()
+	player.Use("keycard", "reader", model.ConsoleDisplay{})
+
+	//Assert
+	if refused != "The reader beeps angrily.\n" {
+		t.Errorf("Expected the uncharged keycard to be refused, got %q", refused)
+	}
+	if first != "The door clicks open.\n" {
+		t.Errorf("Expected the charged keycard to work, got %q", first)
+	}
+	if description != "A keycard with 1 uses left." {
+		t.Errorf("Expected the description to show the uses left, got %q", description)
+	}
+	if _, ok := player.Inventory["keycard"]; ok || player.CarriedWeight != 0 {
+		t.Errorf("Expected the keycard to be used up after its last use, carrying %d", player.CarriedWeight)
+	}
+}
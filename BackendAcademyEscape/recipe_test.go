@@ -0,0 +1,149 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"strings"
+	"testing"
+)
+
+// addFridge puts a fridge with Rosie's milk in the break room, and a recipe
+// for adding the milk to her tea.
+func addFridge(game *model.Game) {
+	game.AddContainer("break-room", &model.Container{
+		Name:        "fridge",
+		Description: "A humming fridge covered in passive-aggressive notes about labelling your lunch.",
+		Closable:    true,
+		Closed:      true,
+		Items:       []*model.Item{{Name: "milk", Description: "A carton of semi-skimmed milk, labelled 'ROSIE'.", Weight: 1}},
+	})
+	game.AddRecipe(&model.Recipe{
+		Inputs:  []string{"tea", "milk"},
+		Result:  &model.Item{Name: "tea", Description: "A cup of Yorkshire tea with a splash of milk, just how Rosie likes it.", Weight: 2},
+		Outcome: "You add a splash of milk to the tea. Perfect.\n",
+	})
+}
+
+// makeTeaAndFetchMilk plays until the player holds both the tea and the milk.
+func makeTeaAndFetchMilk(game *model.Game) {
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+	run(game, "leave")
+	run(game, "open", "fridge")
+	run(game, "take", "milk", "from", "fridge")
+}
+
+func TestCombiningTeaWithMilkStillPleasesRosie(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addFridge(game)
+	makeTeaAndFetchMilk(game)
+
+	//Act
+	combined := run(game, "combine", "tea", "with", "milk")
+	inventory := run(game, "inventory")
+	run(game, "approach", "rosie")
+	used := run(game, "use", "tea")
+
+	//Assert
+	if !strings.HasPrefix(combined.Message, "You add a splash of milk to the tea. Perfect.\n") {
+		t.Errorf("Expected the milk to go in the tea, got %q", combined.Message)
+	}
+	if !strings.Contains(inventory.Message, "Available space: 18\n") || !strings.Contains(inventory.Message, "splash of milk") || strings.Contains(inventory.Message, "- milk:") {
+		t.Errorf("Expected only the milky tea to be carried, got %q", inventory.Message)
+	}
+	if !strings.HasPrefix(used.Message, "Cheers! I needed that...") {
+		t.Errorf("Expected Rosie to take the milky tea, got %q", used.Message)
+	}
+}
+
+func TestRecipesCanTakeSeveralSteps(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	addFridge(game)
+	addCupboard(game)
+	elevenses := &model.Event{Description: "elevenses", Outcome: "Rosie nods approvingly.\n"}
+	game.AddRecipe(&model.Recipe{Inputs: []string{"biscuits", "milk"}, Result: &model.Item{Name: "soggy-biscuits", Description: "Biscuits, dunked too long.", Weight: 1}})
+	game.AddRecipe(&model.Recipe{Inputs: []string{"soggy-biscuits", "tea"}, Keeps: []string{"tea"}, Outcome: "Elevenses is served.\n", Event: elevenses})
+	makeTeaAndFetchMilk(game)
+	run(game, "examine", "cupboard")
+
+	//Act
+	wrong := run(game, "combine", "tea", "with", "biscuits")
+	dunked := run(game, "use", "milk", "on", "biscuits")
+	served := run(game, "combine", "soggy-biscuits", "tea")
+	inventory := run(game, "inventory")
+
+	//Assert
+	if wrong.Message != "You can't combine tea with biscuits.\n" {
+		t.Errorf("Expected no recipe for tea with biscuits, got %q", wrong.Message)
+	}
+	if !strings.HasPrefix(dunked.Message, "You combine milk with biscuits to make soggy-biscuits.\n") {
+		t.Errorf("Expected the first step to make soggy-biscuits, got %q", dunked.Message)
+	}
+	if !strings.HasPrefix(served.Message, "Elevenses is served.\nRosie nods approvingly.\n") || !elevenses.Triggered {
+		t.Errorf("Expected the second step to trigger its event, got %q", served.Message)
+	}
+	if !strings.Contains(inventory.Message, "- tea:") || strings.Contains(inventory.Message, "soggy-biscuits") {
+		t.Errorf("Expected to keep the tea and use up the biscuits, got %q", inventory.Message)
+	}
+}
+
+func TestCombiningWontMakeAnItemAlreadyHeld(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddItem("break-room", &model.Item{Name: "teabag", Description: "A spare teabag.", Weight: 1})
+	game.AddItem("break-room", &model.Item{Name: "hot-water", Description: "A mug of hot water.", Weight: 1})
+	game.AddRecipe(&model.Recipe{
+		Inputs: []string{"teabag", "hot-water"},
+		Result: &model.Item{Name: "tea", Description: "Another cup of tea.", Weight: 2},
+	})
+	run(game, "approach", "kettle")
+	run(game, "take", "tea")
+	run(game, "leave")
+
+	//Act
+	refused := run(game, "combine", "teabag", "with", "hot-water")
+	look := run(game, "look")
+	inventory := run(game, "inventory")
+
+	//Assert
+	if refused.Message != "You already have tea, so you can't make another.\n" {
+		t.Errorf("Expected the combine to be refused, got %q", refused.Message)
+	}
+	if !strings.Contains(look.Message, "- teabag:") || !strings.Contains(look.Message, "- hot-water:") {
+		t.Errorf("Expected the inputs to be left alone, got %q", look.Message)
+	}
+	if !strings.Contains(inventory.Message, "Available space: 18\n") {
+		t.Errorf("Expected the tea to be counted once, got %q", inventory.Message)
+	}
+}
+
+func TestCombiningCopiesWhatTheResultHolds(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	game.AddItem("break-room", &model.Item{Name: "bread", Description: "Two slices of bread.", Weight: 1})
+	game.AddItem("break-room", &model.Item{Name: "tub", Description: "An empty plastic tub.", Weight: 1})
+	lunchbox := &model.Item{
+		Name:        "lunchbox",
+		Description: "A tub with a sandwich in it.",
+		Weight:      2,
+		Container:   &model.Container{Name: "lunchbox", Capacity: 2, Items: []*model.Item{{Name: "sandwich", Description: "A cheese sandwich.", Weight: 1}}},
+	}
+	game.AddRecipe(&model.Recipe{Inputs: []string{"bread", "tub"}, Result: lunchbox})
+	run(game, "combine", "bread", "with", "tub")
+
+	//Act
+	taken := run(game, "take", "sandwich", "from", "lunchbox")
+
+	//Assert
+	if !strings.Contains(taken.Message, "sandwich") {
+		t.Errorf("Expected to take the sandwich out of the lunchbox, got %q", taken.Message)
+	}
+	if len(lunchbox.Container.Items) != 1 {
+		t.Errorf("Expected the recipe's lunchbox to keep its sandwich, got %d items", len(lunchbox.Container.Items))
+	}
+}
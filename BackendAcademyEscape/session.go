@@ -0,0 +1,308 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	transcriptCommand     = "command"
+	transcriptFacilitator = "facilitator_message"
+)
+
+type Session struct {
+	ID         string
+	WorldID    string
+	PlayerID   string
+	PlayerName string
+	Game       *model.Game
+	CreatedAt  time.Time
+	Outbox     *Outbox[Frame]
+	Events     *Outbox[model.GameEvent]
+	commandMu  sync.Mutex
+	lastActive time.Time
+	transcript []TranscriptEntry
+	stopEvents func()
+}
+
+// Run plays a command and publishes the response to the session's
+// streaming subscribers. Commands are run one at a time so that frames are
+// numbered in the order the game saw them, whichever transport sent them.
+func (session *Session) Run(playerInput model.PlayerInput) model.GameResponse {
+	session.commandMu.Lock()
+	defer session.commandMu.Unlock()
+
+	response := session.Game.RunGameAs(session.PlayerID, playerInput)
+
+	session.lastActive = time.Now()
+	session.record(TranscriptEntry{
+		Kind:     transcriptCommand,
+		Command:  playerInput.Command,
+		Args:     playerInput.Args,
+		Message:  response.Message,
+		GameOver: response.GameOver,
+	})
+	session.Outbox.Publish(Frame{Type: frameResponse, Message: response.Message, GameOver: response.GameOver})
+	return response
+}
+
+// SendFacilitatorMessage queues a message to be shown at the top of the
+// player's next response.
+func (session *Session) SendFacilitatorMessage(message string) {
+	session.commandMu.Lock()
+	defer session.commandMu.Unlock()
+
+	session.Game.TellPlayer(session.PlayerID, message)
+	session.record(TranscriptEntry{Kind: transcriptFacilitator, Message: message})
+}
+
+// Summary reports the session's progress without waiting behind more than
+// the command currently running.
+func (session *Session) Summary() SessionSummary {
+	session.commandMu.Lock()
+	defer session.commandMu.Unlock()
+
+	progress, _ := session.Game.Progress(session.PlayerID)
+	var remaining *int
+	if state, ok := session.Game.Countdown(); ok {
+		remaining = remainingSeconds(state)
+	}
+	return SessionSummary{
+		ID:                        session.ID,
+		WorldID:                   session.WorldID,
+		PlayerName:                progress.PlayerName,
+		Room:                      progress.Room,
+		GameOver:                  progress.GameOver,
+		TriggeredEvents:           progress.TriggeredEvents,
+		RemainingPasswordAttempts: progress.RemainingPasswordAttempts,
+		IdleSeconds:               int64(time.Since(session.lastActive).Seconds()),
+		RemainingSeconds:          remaining,
+		CreatedAt:                 session.CreatedAt,
+	}
+}
+
+// Transcript returns the entries recorded after the given seq.
+func (session *Session) Transcript(after int) []TranscriptEntry {
+	session.commandMu.Lock()
+	defer session.commandMu.Unlock()
+
+	entries := []TranscriptEntry{}
+	if after < len(session.transcript) {
+		entries = append(entries, session.transcript[after:]...)
+	}
+	return entries
+}
+
+func (session *Session) record(entry TranscriptEntry) {
+	entry.Seq = len(session.transcript) + 1
+	entry.Time = time.Now()
+	session.transcript = append(session.transcript, entry)
+}
+
+// Listen pushes what the player hears from elsewhere, such as chat,
+// scheduled events and the countdown running out, as notification frames
+// until the returned function is called. Without it, those messages wait
+// for the top of the player's next response.
+func (session *Session) Listen() func() {
+	stop, err := session.Game.Listen(session.PlayerID, func(message string, gameOver bool) {
+		session.Outbox.Publish(Frame{Type: frameNotification, Message: message, GameOver: gameOver})
+	})
+	if err != nil {
+		return func() {}
+	}
+	return stop
+}
+
+// Close tells streaming subscribers that the session has ended and
+// disconnects them.
+func (session *Session) Close() {
+	session.stopEvents()
+	session.Outbox.Publish(Frame{Type: frameClosed, Message: "The session has ended."})
+	session.Outbox.Close()
+	session.Events.Close()
+}
+
+// World is a game shared by the players of one or more sessions.
+type World struct {
+	ID       string
+	Game     *model.Game
+	sessions int
+	timerMu  sync.Mutex
+	timer    *time.Timer
+}
+
+// WorldOptions configure a new world. They cannot be changed by players who
+// join it later.
+type WorldOptions struct {
+	DisableChat bool
+	TimeLimit   time.Duration
+}
+
+// watchCountdown arms a timer to end the game when its time limit runs out,
+// so that streaming players are told without having to send a command. It
+// is called again whenever a facilitator changes the countdown.
+func (world *World) watchCountdown() {
+	world.timerMu.Lock()
+	defer world.timerMu.Unlock()
+
+	if world.timer != nil {
+		world.timer.Stop()
+		world.timer = nil
+	}
+	state, ok := world.Game.Countdown()
+	if !ok || state.Paused || world.Game.IsOver() {
+		return
+	}
+	world.timer = time.AfterFunc(state.Remaining, func() {
+		if !world.Game.CheckTimeout() {
+			world.watchCountdown()
+		}
+	})
+}
+
+func (world *World) stopCountdown() {
+	world.timerMu.Lock()
+	defer world.timerMu.Unlock()
+	if world.timer != nil {
+		world.timer.Stop()
+	}
+}
+
+var errWorldNotFound = errors.New("world not found")
+
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	worlds   map[string]*World
+}
+
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]*Session), worlds: make(map[string]*World)}
+}
+
+// Create joins the named player to the world with the given ID, or to a
+// fresh world if worldID is empty, and registers the session under a new
+// random ID. Each session receives the events of everybody in its world,
+// apart from chat meant for other players. options only apply to a new world.
+func (store *SessionStore) Create(playerName string, worldID string, options WorldOptions) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	world, ok := store.worlds[worldID]
+	if worldID == "" {
+		newWorldID, err := newSessionID()
+		if err != nil {
+			return nil, err
+		}
+		world = &World{ID: newWorldID, Game: &model.Game{}}
+		world.Game.SetupWorld()
+		if options.DisableChat {
+			world.Game.DisableChat()
+		}
+		if options.TimeLimit > 0 {
+			world.Game.SetTimeLimit(options.TimeLimit)
+			world.watchCountdown()
+		}
+	} else if !ok {
+		return nil, errWorldNotFound
+	}
+
+	playerID, err := world.Game.Join(playerName)
+	if err != nil {
+		return nil, err
+	}
+	progress, _ := world.Game.Progress(playerID)
+
+	session := &Session{
+		ID:         id,
+		WorldID:    world.ID,
+		PlayerID:   playerID,
+		PlayerName: progress.PlayerName,
+		Game:       world.Game,
+		CreatedAt:  time.Now(),
+		Outbox:     NewOutbox[Frame](),
+		Events:     NewOutbox[model.GameEvent](),
+		lastActive: time.Now(),
+	}
+	session.stopEvents = world.Game.OnEvent(func(event model.GameEvent) {
+		if !event.VisibleTo(session.PlayerName) {
+			return
+		}
+		session.Events.Publish(event)
+	})
+
+	world.sessions++
+	store.worlds[world.ID] = world
+	store.sessions[id] = session
+	return session, nil
+}
+
+func (store *SessionStore) GetWorld(id string) (*World, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	world, ok := store.worlds[id]
+	return world, ok
+}
+
+func (store *SessionStore) Get(id string) (*Session, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	session, ok := store.sessions[id]
+	return session, ok
+}
+
+// List returns every session, oldest first.
+func (store *SessionStore) List() []*Session {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	sessions := make([]*Session, 0, len(store.sessions))
+	for _, session := range store.sessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions
+}
+
+func (store *SessionStore) Delete(id string) bool {
+	store.mu.Lock()
+	session, ok := store.sessions[id]
+	delete(store.sessions, id)
+	if ok {
+		if world := store.worlds[session.WorldID]; world != nil {
+			world.sessions--
+			if world.sessions == 0 {
+				world.stopCountdown()
+				delete(store.worlds, world.ID)
+			}
+		}
+	}
+	store.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	session.Game.Leave(session.PlayerID)
+	session.Close()
+	return true
+}
+
+func newSessionID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
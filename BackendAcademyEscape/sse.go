@@ -0,0 +1,96 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const sseKeepAlive = 15 * time.Second
+
+// eventStream documents a text/event-stream response whose data lines are
+// JSON encodings of Event.
+type eventStream struct {
+	Event any
+}
+
+// streamEvents sends the session's game events as Server-Sent Events until
+// the client disconnects or the session ends. Each event's id is its seq, so
+// a reconnecting EventSource resumes through the Last-Event-ID header.
+func (s *server) streamEvents(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	lastEventID := request.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = request.URL.Query().Get("last_event_id")
+	}
+	var lastSeen int64
+	if lastEventID != "" {
+		parsed, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || parsed < 0 {
+			writeError(writer, http.StatusBadRequest, errorInvalidInput, "The last event id must be a non-negative integer.")
+			return
+		}
+		lastSeen = parsed
+	}
+
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		writeError(writer, http.StatusInternalServerError, errorInternal, "Streaming is not supported.")
+		return
+	}
+
+	subscriber, replay, complete := session.Events.Subscribe(lastSeen)
+	defer session.Events.Unsubscribe(subscriber)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	if !complete {
+		fmt.Fprint(writer, ": some events are too old to be replayed\n\n")
+	}
+	for _, entry := range replay {
+		if writeEvent(writer, entry) != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case entry, ok := <-subscriber:
+			if !ok {
+				return
+			}
+			if writeEvent(writer, entry) != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(writer http.ResponseWriter, entry Sequenced[model.GameEvent]) error {
+	data, err := json.Marshal(entry.Value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", entry.Seq, entry.Value.Type, data)
+	return err
+}
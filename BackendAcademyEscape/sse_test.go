@@ -0,0 +1,145 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type receivedEvent struct {
+	id    string
+	event model.GameEvent
+}
+
+// openEventStream connects to the session's event stream and returns a
+// channel of the events it receives.
+func openEventStream(t *testing.T, testServer *httptest.Server, id string, lastEventID string) <-chan receivedEvent {
+	t.Helper()
+	request, _ := http.NewRequest(http.MethodGet, testServer.URL+"/api/v1/sessions/"+id+"/events", nil)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", contentType)
+	}
+
+	events := make(chan receivedEvent, 16)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(response.Body)
+		var current receivedEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				current.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.event)
+			case line == "" && current.id != "":
+				events <- current
+				current = receivedEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan receivedEvent) receivedEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("Expected an event, the stream ended")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an event, got none")
+	}
+	return receivedEvent{}
+}
+
+func TestEventStreamEmitsTypedEvents(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	t.Cleanup(testServer.Close)
+	session := createTestSession(t, s.handler())
+	events := openEventStream(t, testServer, session.ID, "")
+	path := "/api/v1/sessions/" + session.ID + "/commands"
+
+	//Act
+	performRequest(s.handler(), http.MethodPost, path, `{"command":"approach","args":["kettle"]}`)
+	performRequest(s.handler(), http.MethodPost, path, `{"command":"take","args":["tea"]}`)
+	performRequest(s.handler(), http.MethodPost, path, `{"command":"exit"}`)
+
+	//Assert
+	expected := []struct {
+		eventType model.GameEventType
+		detail    string
+	}{
+		{model.CommandReceived, "start"},
+		{model.CommandReceived, "approach"},
+		{model.CommandReceived, "take"},
+		{model.ItemTaken, "tea"},
+		{model.CommandReceived, "exit"},
+		{model.GameEnded, "Thank you for playing!"},
+	}
+	for _, want := range expected {
+		got := nextEvent(t, events).event
+		detail := got.Command + got.Item + got.Message
+		if got.Type != want.eventType || detail != want.detail {
+			t.Errorf("Expected %s %q, got %s %q", want.eventType, want.detail, got.Type, detail)
+		}
+	}
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	t.Cleanup(testServer.Close)
+	session := createTestSession(t, s.handler())
+	performRequest(s.handler(), http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", `{"command":"look"}`)
+
+	//Act
+	events := openEventStream(t, testServer, session.ID, "1")
+	first := nextEvent(t, events)
+
+	//Assert
+	if first.id != "2" || first.event.Command != "look" {
+		t.Errorf("Expected event 2 for look, got %s %+v", first.id, first.event)
+	}
+}
+
+func TestEventStreamEndsWithSession(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	t.Cleanup(testServer.Close)
+	session := createTestSession(t, s.handler())
+	events := openEventStream(t, testServer, session.ID, "")
+	nextEvent(t, events)
+
+	//Act
+	s.sessions.Delete(session.ID)
+
+	//Assert
+	select {
+	case event, ok := <-events:
+		if ok {
+			t.Errorf("Expected the stream to end, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the stream to end when the session was deleted")
+	}
+}
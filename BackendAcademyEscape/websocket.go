@@ -0,0 +1,188 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxFrameBytes  = 4096
+	directFrameCap = 8
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+	Error: func(writer http.ResponseWriter, request *http.Request, status int, reason error) {
+		writeError(writer, status, errorInvalidInput, reason.Error())
+	},
+}
+
+// checkOrigin lets browsers open a WebSocket from the same host or one of
+// the allowedOrigins. CORS doesn't apply to upgrades, so without this any
+// site could play a session from its visitors' browsers. Clients that
+// aren't browsers send no Origin and are let in.
+func checkOrigin(request *http.Request) bool {
+	origin := request.Header.Get("Origin")
+	if origin == "" || slices.Contains(allowedOrigins, origin) {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, request.Host)
+}
+
+// connectWebSocket upgrades the request and plays the session over the
+// connection, pushing what the player hears from elsewhere as it happens.
+// Reconnecting clients pass the seq of the last frame they saw
+// as last_seq to have everything published since replayed to them.
+func (s *server) connectWebSocket(writer http.ResponseWriter, request *http.Request) {
+	session, ok := s.lookupSession(writer, request)
+	if !ok {
+		return
+	}
+
+	var lastSeen int64
+	if value := request.URL.Query().Get("last_seq"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			writeError(writer, http.StatusBadRequest, errorInvalidInput, "last_seq must be a non-negative integer.")
+			return
+		}
+		lastSeen = parsed
+	}
+
+	conn, err := upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		fmt.Println("Error upgrading connection:", err)
+		return
+	}
+
+	subscriber, replay, complete := session.Outbox.Subscribe(lastSeen)
+	if !complete {
+		gap := Frame{Type: frameError, Code: errorResumeIncomplete, Message: "Some messages are too old to be replayed."}
+		replay = append([]Sequenced[Frame]{{Value: gap}}, replay...)
+	}
+	direct := make(chan Frame, directFrameCap)
+	stopListening := session.Listen()
+
+	go writeFrames(conn, replay, subscriber, direct)
+	readFrames(conn, session, direct)
+	stopListening()
+	session.Outbox.Unsubscribe(subscriber)
+}
+
+// readFrames runs the player's commands until the connection is closed.
+// Frames that are not commands are answered on the direct channel, which
+// only this connection sees.
+func readFrames(conn *websocket.Conn, session *Session, direct chan<- Frame) {
+	defer close(direct)
+
+	conn.SetReadLimit(maxFrameBytes)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				fmt.Println("Error reading frame:", err)
+			}
+			return
+		}
+
+		var frame Frame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			sendDirect(direct, Frame{Type: frameError, Code: errorInvalidInput, Message: "Frames must be valid JSON."})
+			continue
+		}
+
+		switch {
+		case frame.Type != frameCommand:
+			sendDirect(direct, Frame{Type: frameError, Code: errorInvalidInput, Message: fmt.Sprintf("Unsupported frame type %q.", frame.Type)})
+		case strings.TrimSpace(frame.Command) == "":
+			sendDirect(direct, Frame{Type: frameError, Code: errorInvalidInput, Message: "The command must not be empty."})
+		case session.Game.IsOverFor(session.PlayerID):
+			sendDirect(direct, Frame{Type: frameError, Code: errorGameOver, Message: "The game is over, start a new session to play again."})
+		default:
+			session.Run(model.PlayerInput{Command: frame.Command, Args: frame.Args})
+		}
+	}
+}
+
+// sendDirect drops the frame rather than block reading when the client is
+// not keeping up with its own errors.
+func sendDirect(direct chan<- Frame, frame Frame) {
+	select {
+	case direct <- frame:
+	default:
+	}
+}
+
+// writeFrames is the only goroutine that writes to the connection. It sends
+// the replayed frames first, then published and direct frames as they
+// arrive, pinging the client to keep the connection alive.
+func writeFrames(conn *websocket.Conn, replay []Sequenced[Frame], subscriber <-chan Sequenced[Frame], direct <-chan Frame) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for _, entry := range replay {
+		if !writeFrame(conn, sequencedFrame(entry)) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-subscriber:
+			if !ok {
+				conn.SetWriteDeadline(time.Now().Add(writeWait))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if !writeFrame(conn, sequencedFrame(entry)) {
+				return
+			}
+		case frame, ok := <-direct:
+			if !ok {
+				return
+			}
+			if !writeFrame(conn, frame) {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func sequencedFrame(entry Sequenced[Frame]) Frame {
+	frame := entry.Value
+	frame.Seq = entry.Seq
+	return frame
+}
+
+func writeFrame(conn *websocket.Conn, frame Frame) bool {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(frame) == nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialSession(t *testing.T, testServer *httptest.Server, id string, query string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(testServer.URL, "http") + "/api/v1/sessions/" + id + "/ws" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Expected to connect to %s, got %v", url, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) Frame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var frame Frame
+	if err := conn.ReadJSON(&frame); err != nil {
+		t.Fatalf("Expected a frame, got %v", err)
+	}
+	return frame
+}
+
+func TestWebSocketRunsCommands(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	conn.WriteJSON(Frame{Type: frameCommand, Command: "look"})
+	frame := readFrame(t, conn)
+
+	//Assert
+	if frame.Type != frameResponse || frame.Seq != 1 {
+		t.Errorf("Expected response frame with seq 1, got %+v", frame)
+	}
+	if !strings.HasPrefix(frame.Message, "You are in break-room") {
+		t.Errorf("Expected the room description, got %q", frame.Message)
+	}
+}
+
+func TestWebSocketRejectsInvalidFrames(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	conn.WriteMessage(websocket.TextMessage, []byte("not json"))
+	invalid := readFrame(t, conn)
+	conn.WriteJSON(Frame{Type: "dance"})
+	unsupported := readFrame(t, conn)
+
+	//Assert
+	if invalid.Type != frameError || invalid.Code != errorInvalidInput || invalid.Seq != 0 {
+		t.Errorf("Expected an unsequenced invalid_input error, got %+v", invalid)
+	}
+	if unsupported.Type != frameError || unsupported.Code != errorInvalidInput {
+		t.Errorf("Expected an invalid_input error, got %+v", unsupported)
+	}
+}
+
+func TestWebSocketPushesNotificationsAndRestCommands(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	session.SendFacilitatorMessage("The kettle has boiled.")
+	notification := readFrame(t, conn)
+	performRequest(s.handler(), "POST", "/api/v1/sessions/"+session.ID+"/commands", `{"command":"inventory"}`)
+	response := readFrame(t, conn)
+
+	//Assert
+	if notification.Type != frameNotification || notification.Message != "Message from the facilitator: The kettle has boiled." {
+		t.Errorf("Expected the notification, got %+v", notification)
+	}
+	if response.Type != frameResponse || !strings.HasPrefix(response.Message, "Your inventory is empty.") {
+		t.Errorf("Expected the inventory response sent over REST, got %+v", response)
+	}
+}
+
+func TestWebSocketPushesWhatOtherPlayersSay(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	ada, _ := s.sessions.Create("Ada", "", WorldOptions{})
+	grace, _ := s.sessions.Create("Grace", ada.WorldID, WorldOptions{})
+	conn := dialSession(t, testServer, grace.ID, "")
+
+	//Act
+	ada.Run(model.PlayerInput{Command: "say", Args: []string{"the", "kettle", "works"}})
+	heard := readFrame(t, conn)
+	response := grace.Run(model.PlayerInput{Command: "time"})
+
+	//Assert
+	if heard.Type != frameNotification || heard.Message != "Ada says: the kettle works" || heard.GameOver {
+		t.Errorf("Expected Ada's words to be pushed to Grace, got %+v", heard)
+	}
+	if strings.Contains(response.Message, "kettle works") {
+		t.Errorf("Expected the pushed message not to be repeated in the next response, got %q", response.Message)
+	}
+}
+
+func TestWebSocketResumesAfterReconnect(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	first := dialSession(t, testServer, session.ID, "")
+	first.WriteJSON(Frame{Type: frameCommand, Command: "look"})
+	seen := readFrame(t, first)
+	first.Close()
+
+	//Act
+	session.SendFacilitatorMessage("Missed while disconnected.")
+	second := dialSession(t, testServer, session.ID, "?last_seq=1")
+	replayed := readFrame(t, second)
+
+	//Assert
+	if seen.Seq != 1 {
+		t.Fatalf("Expected the first frame to have seq 1, got %d", seen.Seq)
+	}
+	if replayed.Seq != 2 || replayed.Message != "Message from the facilitator: Missed while disconnected." {
+		t.Errorf("Expected the missed notification to be replayed, got %+v", replayed)
+	}
+}
+
+func TestWebSocketClosesWithSession(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	conn := dialSession(t, testServer, session.ID, "")
+
+	//Act
+	s.sessions.Delete(session.ID)
+	frame := readFrame(t, conn)
+	_, _, err := conn.ReadMessage()
+
+	//Assert
+	if frame.Type != frameClosed {
+		t.Errorf("Expected a closed frame, got %+v", frame)
+	}
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("Expected a normal close, got %v", err)
+	}
+}
+
+func TestWebSocketRejectsOtherOrigins(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("tester", "", WorldOptions{})
+	url := "ws" + strings.TrimPrefix(testServer.URL, "http") + "/api/v1/sessions/" + session.ID + "/ws"
+
+	//Act
+	_, rejected, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"http://evil.example"}})
+	allowed, _, allowedErr := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {allowedOrigins[0]}})
+
+	//Assert
+	if err == nil || rejected == nil || rejected.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected another site to be refused with %d, got %v", http.StatusForbidden, err)
+	}
+	if allowedErr != nil {
+		t.Errorf("Expected the web client to connect, got %v", allowedErr)
+	} else {
+		allowed.Close()
+	}
+}
+
+func TestWebSocketEndsTheGameOnlyForThePlayerWhoExited(t *testing.T) {
+	//Arrange
+	s := newServer()
+	testServer := httptest.NewServer(s.handler())
+	defer testServer.Close()
+	session, _ := s.sessions.Create("Ada", "", WorldOptions{})
+	other, _ := s.sessions.Create("Grace", session.WorldID, WorldOptions{})
+	session.Run(model.PlayerInput{Command: "exit"})
+	conn := dialSession(t, testServer, session.ID, "?last_seq=1")
+	otherConn := dialSession(t, testServer, other.ID, "")
+
+	//Act
+	conn.WriteJSON(Frame{Type: frameCommand, Command: "look"})
+	refused := readFrame(t, conn)
+	otherConn.WriteJSON(Frame{Type: frameCommand, Command: "look"})
+	played := readFrame(t, otherConn)
+
+	//Assert
+	if refused.Type != frameError || refused.Code != errorGameOver {
+		t.Errorf("Expected the player who exited to be refused, got %+v", refused)
+	}
+	if played.Type != frameResponse || !strings.HasPrefix(played.Message, "You are in break-room") {
+		t.Errorf("Expected the other player to keep playing, got %+v", played)
+	}
+}
+
+func TestOutboxReportsIncompleteReplay(t *testing.T) {
+	//Arrange
+	outbox := NewOutbox[Frame]()
+	for i := 0; i < outboxBacklog+5; i++ {
+		outbox.Publish(Frame{Type: frameNotification, Message: "tick"})
+	}
+
+	//Act
+	_, replay, complete := outbox.Subscribe(0)
+	_, recent, recentComplete := outbox.Subscribe(outboxBacklog)
+
+	//Assert
+	if complete || len(replay) != outboxBacklog {
+		t.Errorf("Expected an incomplete replay of %d frames, got %d (complete %v)", outboxBacklog, len(replay), complete)
+	}
+	if !recentComplete || len(recent) != 5 {
+		t.Errorf("Expected a complete replay of 5 frames, got %d (complete %v)", len(recent), recentComplete)
+	}
+}
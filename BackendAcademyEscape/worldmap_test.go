@@ -0,0 +1,90 @@
+package main
+
+import (
+	"academy-adventure-game/model"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMapDrawsTheExploredRooms(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+	giveRosieTea(game)
+	run(game, "take", "lanyard")
+	run(game, "move", "south")
+
+	//Act
+	drawn := run(game, "map")
+
+	//Assert
+	expected := " [break-room]\n      |\n[*coding-lab*]--------[?]\n"
+	if !strings.HasPrefix(drawn.Message, expected) {
+		t.Errorf("Expected the map to start with %q, got %q", expected, drawn.Message)
+	}
+	if !strings.Contains(drawn.Message, "east: terminal-room\n") {
+		t.Errorf("Expected the directions to be listed under the map, got %q", drawn.Message)
+	}
+}
+
+func TestMapMarksLockedExits(t *testing.T) {
+	//Arrange
+	game := &model.Game{}
+	game.SetupGame()
+
+	//Act
+	drawn := run(game, "map")
+
+	//Assert
+	if !strings.HasPrefix(drawn.Message, "[*break-room*]\n      x\n     [?]\n") {
+		t.Errorf("Expected the locked door south, got %q", drawn.Message)
+	}
+}
+
+func TestMapLayoutEndpointKeepsRoomsInPlace(t *testing.T) {
+	//Arrange
+	s := newServer()
+	handler := s.handler()
+	session := createTestSession(t, handler)
+	for _, command := range []string{
+		`{"command":"approach","args":["kettle"]}`,
+		`{"command":"take","args":["tea"]}`,
+		`{"command":"approach","args":["rosie"]}`,
+		`{"command":"use","args":["tea"]}`,
+		`{"command":"take","args":["lanyard"]}`,
+		`{"command":"move","args":["south"]}`,
+	} {
+		performRequest(handler, http.MethodPost, "/api/v1/sessions/"+session.ID+"/commands", command)
+	}
+
+	//Act
+	recorder := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID+"/map", "")
+	svg := performRequest(handler, http.MethodGet, "/api/v1/sessions/"+session.ID+"/map.svg", "")
+
+	//Assert
+	var layout model.MapLayout
+	if err := json.NewDecoder(recorder.Body).Decode(&layout); err != nil {
+		t.Fatal(err)
+	}
+	expected := []model.MapRoom{
+		{Name: "break-room", X: 0, Y: 0, Visited: true},
+		{Name: "coding-lab", X: 0, Y: 1, Visited: true, Current: true},
+		{X: 1, Y: 1},
+	}
+	if len(layout.Rooms) != len(expected) {
+		t.Fatalf("Expected %d rooms, got %+v", len(expected), layout.Rooms)
+	}
+	for i, room := range expected {
+		if layout.Rooms[i] != room {
+			t.Errorf("Expected room %d to be %+v, got %+v", i, room, layout.Rooms[i])
+		}
+	}
+	if layout.Width != 2 || layout.Height != 2 || len(layout.Exits) != 3 {
+		t.Errorf("Expected a 2 by 2 grid with 3 exits, got %+v", layout)
+	}
+	if !strings.Contains(svg.Body.String(), `class="room current"`) || !strings.Contains(svg.Body.String(), `class="room unvisited"`) {
+		t.Errorf("Expected the SVG to mark the current and unvisited rooms, got %q", svg.Body.String())
+	}
+}
@@ -36,20 +36,18 @@ function Game() {
     const [selectedArgument, setSelectedArgument] = useState('');
     const [output, setOutput] = useState('');
     const [gameStarted, setGameStarted] = useState(false);
+    const [sessionId, setSessionId] = useState('');
+
+    const apiUrl = 'http://localhost:8080/api/v1';
 
     const startGame = async () => {
-        const commandArgsToSend = { command: "start", args: [] };
         try {
-            const requestData = {
-                method: "POST",
-                headers: { "Content-Type": "application/json" },
-                body: JSON.stringify(commandArgsToSend),
-            };
-            const response = await fetch('http://localhost:8080/GameResponse', requestData);
+            const response = await fetch(`${apiUrl}/sessions`, { method: "POST" });
             if (!response.ok) {
                 throw new Error('Failed to fetch response');
             }
             const data = await response.json();
+            setSessionId(data.id);
             setOutput(data.message);
             setGameStarted(true);
         } catch (error) {
@@ -71,11 +69,11 @@ function Game() {
                 headers: { "Content-Type": "application/json" },
                 body: JSON.stringify(commandArgsToSend),
             };
-            const response = await fetch('http://localhost:8080/GameResponse', requestData);
+            const response = await fetch(`${apiUrl}/sessions/${sessionId}/commands`, requestData);
+            const data = await response.json();
             if (!response.ok) {
-                throw new Error('Failed to fetch response');
+                throw new Error(data.error.message);
             }
-            const data = await response.json();
             setOutput(data.message);
         } catch (error) {
             console.error('Error fetching response', error);
@@ -91,12 +89,8 @@ function Game() {
 
     const fetchAvailableActions = async () => {
         try {
-            const requestData = {
-                method: "POST",
-                headers: { "Content-Type": "application/json" },
-                body: JSON.stringify({ command: selectedCommand }),
-            };
-            const response = await fetch('http://localhost:8080/CommandOptions', requestData);
+            const query = new URLSearchParams({ command: selectedCommand });
+            const response = await fetch(`${apiUrl}/sessions/${sessionId}/actions?${query}`);
             if (!response.ok) {
                 throw new Error('Failed to fetch response');
             }